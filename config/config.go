@@ -1,27 +1,294 @@
 package config
 
 import (
-	"log"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Preferences struct {
+	// Policy selects a logo selection preset: default, official, largest or compatible
+	Policy string `yaml:"policy"`
+	// PreferBackground biases scoring toward logos whose background, read
+	// from their corner pixels, is light or dark, and away from transparent
+	// ones; any disables the bias
+	PreferBackground string `yaml:"prefer_background"`
+	// Scoring overrides individual scoring rules by name (e.g. svg: 20),
+	// applied on top of the policy's weights
+	Scoring   map[string]int `yaml:"scoring"`
 	Preferred struct {
 		MinWidth  int `yaml:"min_width"`
 		MinHeight int `yaml:"min_height"`
+		// MaxWidth and MaxHeight drop larger logos outright; 0 means no limit
+		MaxWidth  int `yaml:"max_width"`
+		MaxHeight int `yaml:"max_height"`
+		// Strict also drops logos below MinWidth/MinHeight instead of only
+		// scoring them lower
+		Strict bool `yaml:"strict"`
 	} `yaml:"preferred"`
+	Validation struct {
+		// VerifyDeclaredSize flags logos whose decoded size differs from the
+		// size declared by the page (link sizes attribute or srcset descriptor)
+		VerifyDeclaredSize bool `yaml:"verify_declared_size"`
+		// DeclaredSizeTolerance is the allowed relative difference (0.25 = 25%)
+		DeclaredSizeTolerance float64 `yaml:"declared_size_tolerance"`
+		// MaxImageBytes rejects images whose Content-Length exceeds it without
+		// downloading them; 0 disables the limit
+		MaxImageBytes int64 `yaml:"max_image_bytes"`
+		// MaxReadBytes caps the bytes read from any image body, whatever its
+		// Content-Length claims, so endless or lying servers cannot exhaust
+		// memory; 0 disables the cap
+		MaxReadBytes int64 `yaml:"max_read_bytes"`
+		// HeadCheck sends a HEAD request before each GET to learn the size
+		// up front; servers without HEAD support fall back to the GET
+		HeadCheck bool `yaml:"head_check"`
+		// AllowedFormats drops logos whose decoded format is not listed
+		// (png, jpeg/jpg, gif, webp, ico, svg); empty accepts all formats
+		AllowedFormats []string `yaml:"allowed_formats"`
+		// Accept is the Accept header sent with image requests so content
+		// negotiation favors formats the validator decodes. AVIF is left out
+		// by default since it cannot be decoded; empty sends no header.
+		Accept string `yaml:"accept"`
+		// RetryTruncated fetches an image once more when its body ends early
+		// or it decodes to 0x0, which flaky connections cause
+		RetryTruncated bool `yaml:"retry_truncated"`
+	} `yaml:"validation"`
+	Extraction struct {
+		// StripQueryParams lists volatile query parameters (cache busters)
+		// removed from candidate URLs so that versions of the same image
+		// collapse into one candidate. An empty list disables stripping.
+		StripQueryParams []string `yaml:"strip_query_params"`
+		// AllowedStatusCodes lists non-2xx page statuses that are still parsed
+		// for candidates (e.g. 304 served from a cache). Other non-2xx pages
+		// are error pages and are skipped.
+		AllowedStatusCodes []int `yaml:"allowed_status_codes"`
+		// MaxConcurrentFetches caps page, manifest and robots.txt fetches in
+		// flight across all workers; 0 disables the limit
+		MaxConcurrentFetches int `yaml:"max_concurrent_fetches"`
+		// Sitemap looks for brand, press, media and about pages in
+		// /sitemap.xml when the homepage yields no candidates (slower)
+		Sitemap bool `yaml:"sitemap"`
+		// SitemapMaxPages caps the sitemap pages fetched per publisher
+		SitemapMaxPages int `yaml:"sitemap_max_pages"`
+		// ProbeWWW also fetches the www. variant of the publisher's homepage;
+		// disable it when the given hosts are canonical to halve page fetches
+		ProbeWWW bool `yaml:"probe_www"`
+		// MaxStylesheets caps the same-site stylesheets fetched per page when
+		// looking for CSS background-image logos; 0 only checks inline CSS
+		MaxStylesheets int `yaml:"max_stylesheets"`
+		// FollowLinks fetches one brand, about or press page linked from the
+		// homepage when the homepage shows no logo (one extra request)
+		FollowLinks bool `yaml:"follow_links"`
+		// AllowHTTP retries a homepage over plain http when https fails with
+		// a connection or TLS error, for legacy sites without https
+		AllowHTTP bool `yaml:"allow_http"`
+	} `yaml:"extraction"`
+	Throttle struct {
+		// Adaptive spaces out requests to a host once its responses slow down
+		Adaptive bool `yaml:"adaptive"`
+		// LatencyThreshold is the latency above which a host counts as slow
+		LatencyThreshold time.Duration `yaml:"latency_threshold"`
+		// InitialDelay is the first delay added between requests to a slow host
+		InitialDelay time.Duration `yaml:"initial_delay"`
+		// MaxDelay caps the delay between requests to the same host
+		MaxDelay time.Duration `yaml:"max_delay"`
+		// RequestsPerSecond caps requests to each host, bursts included
+		// (0 disables the cap)
+		RequestsPerSecond float64 `yaml:"requests_per_second"`
+	} `yaml:"throttle"`
+	Providers struct {
+		// Retries is the number of retries after a third-party provider
+		// (e.g. Clearbit) answers HTTP 429, independent of target-site retries
+		Retries int `yaml:"retries"`
+		// Backoff is the initial wait before a retry, doubled each time
+		Backoff time.Duration `yaml:"backoff"`
+		// MaxBackoff caps a single wait, including server Retry-After hints
+		MaxBackoff time.Duration `yaml:"max_backoff"`
+		// DisableAfter is how many requests may stay rate limited after
+		// retrying before the provider is disabled for the rest of the run;
+		// 0 never disables it
+		DisableAfter int `yaml:"disable_after"`
+		// UseClearbit adds the Clearbit logo API as a fallback candidate;
+		// disable it for offline or privacy-sensitive runs
+		UseClearbit bool `yaml:"use_clearbit"`
+		// ClearbitSize is the logo width in pixels requested from Clearbit
+		// with its size parameter; 0 leaves it to Clearbit's default
+		ClearbitSize int `yaml:"clearbit_size"`
+		// UseGoogleFavicons adds Google's s2 favicon service (128px) as a
+		// second fallback candidate
+		UseGoogleFavicons bool `yaml:"use_google_favicons"`
+	} `yaml:"providers"`
 }
 
-func LoadConfig(path string) Preferences {
+// PreferBackground values
+const (
+	BackgroundAny   = "any"
+	BackgroundLight = "light"
+	BackgroundDark  = "dark"
+)
+
+// DefaultImageAccept is the default Accept header for image requests
+const DefaultImageAccept = "image/webp,image/png,image/svg+xml,image/*;q=0.8"
+
+// DefaultPreferences returns preferences populated with default values
+func DefaultPreferences() Preferences {
 	var cfg Preferences
+	cfg.PreferBackground = BackgroundAny
+	cfg.Validation.DeclaredSizeTolerance = 0.25
+	cfg.Validation.MaxImageBytes = 2 << 20 // 2MB
+	cfg.Validation.MaxReadBytes = 5 << 20  // 5MB
+	cfg.Validation.Accept = DefaultImageAccept
+	cfg.Validation.RetryTruncated = true
+	cfg.Extraction.StripQueryParams = []string{"v", "ver", "version", "cb", "cachebust", "ts"}
+	cfg.Extraction.MaxConcurrentFetches = 10
+	cfg.Extraction.SitemapMaxPages = 3
+	cfg.Extraction.ProbeWWW = true
+	cfg.Extraction.MaxStylesheets = 2
+	cfg.Throttle.Adaptive = true
+	cfg.Throttle.LatencyThreshold = 2 * time.Second
+	cfg.Throttle.InitialDelay = 250 * time.Millisecond
+	cfg.Throttle.MaxDelay = 5 * time.Second
+	cfg.Throttle.RequestsPerSecond = 4
+	cfg.Providers.Retries = 2
+	cfg.Providers.Backoff = time.Second
+	cfg.Providers.MaxBackoff = 10 * time.Second
+	cfg.Providers.DisableAfter = 3
+	cfg.Providers.UseClearbit = true
+	cfg.Providers.ClearbitSize = 128
+	return cfg
+}
+
+// configFile is the layout of a config file: top-level preferences plus
+// optional named profiles. Each profile lists only the settings it changes
+// and is applied on top of the top-level preferences.
+type configFile struct {
+	Preferences `yaml:",inline"`
+	// DefaultProfile names the profile used when none is selected
+	DefaultProfile string               `yaml:"default_profile"`
+	Profiles       map[string]yaml.Node `yaml:"profiles"`
+}
+
+// LoadConfig reads preferences from a YAML file, applying defaults for
+// any settings the file omits. A non-empty profile selects one of the
+// file's profiles; otherwise its default_profile is used, if any.
+func LoadConfig(path, profile string) (Preferences, error) {
+	file := configFile{Preferences: DefaultPreferences()}
 	data, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatalf("Failed to read config file: %v", err)
+		return Preferences{}, fmt.Errorf("failed to read config file: %w", err)
 	}
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		log.Fatalf("Failed to parse config.yaml: %v", err)
+	if err := checkKnownFields(data); err != nil {
+		return Preferences{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
 	}
-	return cfg
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Preferences{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if profile == "" {
+		profile = file.DefaultProfile
+	}
+	if profile == "" {
+		if err := file.Preferences.Validate(); err != nil {
+			return Preferences{}, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		return file.Preferences, nil
+	}
+
+	node, ok := file.Profiles[profile]
+	if !ok {
+		return Preferences{}, fmt.Errorf("unknown profile %q in config file %s (available: %s)",
+			profile, path, strings.Join(profileNames(file.Profiles), ", "))
+	}
+	cfg := file.Preferences
+	if err := node.Decode(&cfg); err != nil {
+		return Preferences{}, fmt.Errorf("failed to parse profile %q in config file %s: %w", profile, path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Preferences{}, fmt.Errorf("invalid profile %q in config file %s: %w", profile, path, err)
+	}
+	return cfg, nil
+}
+
+// checkKnownFields fails on keys that match no setting, in the top-level
+// preferences and in every profile, so that typos such as min_wdith are
+// reported with their line instead of silently ignored
+func checkKnownFields(data []byte) error {
+	var file struct {
+		Preferences    `yaml:",inline"`
+		DefaultProfile string                 `yaml:"default_profile"`
+		Profiles       map[string]Preferences `yaml:"profiles"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(&file)
+	if errors.Is(err, io.EOF) {
+		return nil // Empty file
+	}
+
+	// Drop the Go type from "field x not found in type struct {...}"
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		for i, msg := range typeErr.Errors {
+			if before, _, found := strings.Cut(msg, " in type "); found {
+				typeErr.Errors[i] = before
+			}
+		}
+	}
+	return err
+}
+
+// Validate reports settings with out of range values, such as negative
+// sizes, limits or durations
+func (p Preferences) Validate() error {
+	var errs []error
+	checkNonNegative(&errs, "preferred.min_width", p.Preferred.MinWidth)
+	checkNonNegative(&errs, "preferred.min_height", p.Preferred.MinHeight)
+	checkNonNegative(&errs, "preferred.max_width", p.Preferred.MaxWidth)
+	checkNonNegative(&errs, "preferred.max_height", p.Preferred.MaxHeight)
+	checkNonNegative(&errs, "validation.declared_size_tolerance", p.Validation.DeclaredSizeTolerance)
+	checkNonNegative(&errs, "validation.max_image_bytes", p.Validation.MaxImageBytes)
+	checkNonNegative(&errs, "validation.max_read_bytes", p.Validation.MaxReadBytes)
+	checkNonNegative(&errs, "extraction.max_concurrent_fetches", p.Extraction.MaxConcurrentFetches)
+	checkNonNegative(&errs, "extraction.sitemap_max_pages", p.Extraction.SitemapMaxPages)
+	checkNonNegative(&errs, "extraction.max_stylesheets", p.Extraction.MaxStylesheets)
+	checkNonNegative(&errs, "throttle.latency_threshold", p.Throttle.LatencyThreshold)
+	checkNonNegative(&errs, "throttle.initial_delay", p.Throttle.InitialDelay)
+	checkNonNegative(&errs, "throttle.max_delay", p.Throttle.MaxDelay)
+	checkNonNegative(&errs, "throttle.requests_per_second", p.Throttle.RequestsPerSecond)
+	checkNonNegative(&errs, "providers.retries", p.Providers.Retries)
+	checkNonNegative(&errs, "providers.backoff", p.Providers.Backoff)
+	checkNonNegative(&errs, "providers.max_backoff", p.Providers.MaxBackoff)
+	checkNonNegative(&errs, "providers.disable_after", p.Providers.DisableAfter)
+	checkNonNegative(&errs, "providers.clearbit_size", p.Providers.ClearbitSize)
+	switch p.PreferBackground {
+	case "", BackgroundAny, BackgroundLight, BackgroundDark:
+	default:
+		errs = append(errs, fmt.Errorf("prefer_background must be %s, %s or %s, got %q",
+			BackgroundLight, BackgroundDark, BackgroundAny, p.PreferBackground))
+	}
+	return errors.Join(errs...)
+}
+
+// checkNonNegative appends an error to errs when value is negative
+func checkNonNegative[T int | int64 | float64 | time.Duration](errs *[]error, name string, value T) {
+	if value < 0 {
+		*errs = append(*errs, fmt.Errorf("%s must not be negative, got %v", name, value))
+	}
+}
+
+// profileNames returns the names of profiles in sorted order
+func profileNames(profiles map[string]yaml.Node) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
@@ -1,11 +1,21 @@
 package app
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Tanmay-Thanvi/logo-crawler/config"
@@ -16,19 +26,65 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// shutdownGrace is how long publishers in flight may keep running after an
+// interrupt before they are cancelled
+const shutdownGrace = 5 * time.Second
+
 // LogoCrawlerApp represents the main application
 type LogoCrawlerApp struct {
 	config     *AppConfig
 	prefs      config.Preferences
 	publishers []string
+	headers    map[string]http.Header // Per-publisher headers from the input file
+	names      map[string]string      // Per-publisher display names from the input file
+	bytes      *utils.ByteCounter     // Response body bytes read by the current run
+	metrics    *output.Metrics        // Served on MetricsAddr, nil when disabled
+	checkpoint *output.Checkpoint     // Completed results of the run, nil when disabled
+	client     *http.Client           // HTTP client of the current run, reused for downloads
 }
 
 // AppConfig holds application configuration
 type AppConfig struct {
-	PublisherFilePath string
-	ConfigFilePath    string
-	MaxWorkers        int
-	HTMLOutputPath    string
+	PublisherFilePath   string
+	PublisherColumn     string // CSV column holding publishers; empty reads one per line
+	PublisherNameColumn string // CSV column holding display names, optional
+	DedupPublishers     bool   // Drop publishers resolving to an already listed domain
+	ConfigFilePath      string
+	ConfigProfile       string // Named profile in the config file; empty uses its default
+	MaxWorkers          int
+	MaxRequests         int
+	MaxValidations      int // Concurrent candidate validations per publisher
+	HTTPTimeout         time.Duration
+	PublisherTimeout    time.Duration
+	GlobalTimeout       time.Duration // Deadline for the whole run, 0 for none
+	TopN                int           // Highest scoring logos kept per publisher
+	MinSuccessRate      float64       // Percentage below which Run exits 1, 0 to disable
+	HTMLOutputPath      string
+	ReportRetention     int    // Timestamped HTML reports kept, 0 keeps all
+	HTMLCompact         bool   // Best logo table instead of image grids
+	ErrorsOnly          bool   // HTML and JSON reports list only publishers needing attention
+	ReportSort          string // Order of report results, see output.SortOrders
+	BestOnly            bool   // Reports list only each publisher's best logo
+	JSONOutputPath      string
+	JSONOutputDir       string
+	JSONLOutputPath     string // Stream JSON lines here ("-" for stdout) instead of building reports
+	CSVOutputPath       string
+	TSVOutputPath       string
+	SQLitePath          string
+	DownloadDir         string
+	CacheDir            string
+	CacheTTL            time.Duration
+	UserAgent           string
+	ExtraHeaders        string // Comma-separated Key:Value headers sent to each publisher's own host
+	ProxyURL            string
+	InsecureTLS         bool // Retry certificate failures without verification
+	LogLevel            slog.Level
+	DryRun              bool
+	Bench               bool   // Only print throughput metrics, without reports
+	Quiet               bool   // Only print the final stats line and report paths
+	Verbose             bool   // Print each logo's scoring breakdown
+	MetricsAddr         string // Address serving Prometheus metrics, empty to disable
+	CheckpointFile      string // JSON lines of completed publishers, to resume interrupted runs
 }
 
 // NewLogoCrawlerApp creates a new application instance
@@ -36,47 +92,284 @@ func NewLogoCrawlerApp() *LogoCrawlerApp {
 	return &LogoCrawlerApp{}
 }
 
-// Run executes the main application logic
-func (app *LogoCrawlerApp) Run() {
+// Run executes the main application logic and returns the process exit code:
+// 1 when the success rate falls below the configured minimum, 0 otherwise.
+// SIGINT or SIGTERM stops dispatching publishers, and reports are generated
+// from those completed. "logo-crawler diff" compares two reports instead.
+func (app *LogoCrawlerApp) Run() int {
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		return app.runDiff(os.Args[2:])
+	}
+
 	app.loadEnvironment()
 	app.loadConfiguration()
 	app.loadPublishers()
 	app.displayStartupInfo()
+	defer app.startMetricsServer()()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	if app.config.GlobalTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, app.config.GlobalTimeout)
+		defer cancel()
+	}
+
+	if app.config.JSONLOutputPath != "" {
+		stats := app.streamPublishers(ctx)
+		app.reportInterruption(ctx)
+		stop()
+
+		if app.config.JSONLOutputPath == "-" {
+			log.Print(statsLine(stats)) // Keep stdout pure JSON lines
+		} else {
+			app.displayFinalStats(stats)
+			fmt.Printf("📄 JSON lines written: %s\n", app.config.JSONLOutputPath)
+		}
+		return app.exitCode(stats)
+	}
+
+	results, totalDuration := app.processPublishers(ctx)
+	app.reportInterruption(ctx)
+	stop() // A second interrupt exits immediately
+
+	if app.config.Bench {
+		app.displayBenchmark(results, totalDuration)
+		return 0
+	}
 
-	results, totalDuration := app.processPublishers()
 	app.displayResults(results)
+	if app.config.DryRun {
+		return 0 // Reports need validated logos
+	}
+	results = output.SortResults(results, app.config.ReportSort)
+	if app.config.BestOnly {
+		results = output.BestOnly(results)
+	}
+	app.generateJSONReport(results, totalDuration)
+	app.generateJSONFiles(results)
+	app.generateCSVReport(results)
+	app.generateTSVReport(results)
+	app.generateSQLiteReport(results, totalDuration)
+	app.downloadLogos(results)
 	app.generateHTMLReport(results, totalDuration)
+
+	return app.exitCode(app.calculateStats(results))
 }
 
-// loadEnvironment loads environment variables and .env file
+// exitCode returns 1 when the run's success rate is below MinSuccessRate
+func (app *LogoCrawlerApp) exitCode(stats Stats) int {
+	if app.config.MinSuccessRate > 0 && stats.SuccessRate < app.config.MinSuccessRate {
+		log.Printf("❌ Success rate %.1f%% is below the minimum of %.1f%%", stats.SuccessRate, app.config.MinSuccessRate)
+		return 1
+	}
+	return 0
+}
+
+// reportInterruption tells the user when ctx ended the run early
+func (app *LogoCrawlerApp) reportInterruption(ctx context.Context) {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		app.printf("\n⏰ Global timeout of %v reached: reporting on publishers completed so far\n", app.config.GlobalTimeout)
+	case context.Canceled:
+		app.printf("\n🛑 Interrupted: reporting on publishers completed so far\n")
+	}
+}
+
+// runDiff implements "logo-crawler diff [--html-out path] OLD NEW": it prints
+// which publishers' logos changed between two JSON reports or JSON lines
+// files and writes an HTML diff
+func (app *LogoCrawlerApp) runDiff(args []string) int {
+	flags := flag.NewFlagSet("logo-crawler diff", flag.ExitOnError)
+	htmlOut := flags.String("html-out", "",
+		"path of the HTML diff, empty for logo-crawler-diff.html next to the new report")
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		log.Fatal("❌ Usage: logo-crawler diff [--html-out path] OLD.json NEW.json")
+	}
+
+	diff, err := output.DiffReports(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		log.Fatalf("❌ Failed to diff reports: %v", err)
+	}
+	diff.WriteSummary(os.Stdout)
+
+	path := *htmlOut
+	if path == "" {
+		path = filepath.Join(filepath.Dir(flags.Arg(1)), "logo-crawler-diff.html")
+	}
+	if err := output.NewDiffHTMLGenerator(path).GenerateReport(diff); err != nil {
+		log.Printf("⚠️ Failed to generate HTML diff: %v", err)
+		return 1
+	}
+	fmt.Printf("📄 HTML diff generated: %s\n", path)
+	return 0
+}
+
+// loadEnvironment loads environment variables and .env file, then applies
+// command line flags on top of them
 func (app *LogoCrawlerApp) loadEnvironment() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠️ No .env file found, using system environment variables")
 	}
 
 	app.config = &AppConfig{
-		PublisherFilePath: os.Getenv("PUBLISHER_FILE_PATH"),
-		ConfigFilePath:    os.Getenv("CONFIG_FILE_PATH"),
-		MaxWorkers:        app.getMaxWorkers(),
-		HTMLOutputPath:    app.getHTMLOutputPath(),
+		PublisherFilePath:   os.Getenv("PUBLISHER_FILE_PATH"),
+		PublisherColumn:     os.Getenv("PUBLISHER_CSV_COLUMN"),
+		PublisherNameColumn: os.Getenv("PUBLISHER_NAME_COLUMN"),
+		DedupPublishers:     app.getBoolEnv("DEDUP_PUBLISHERS", false),
+		ConfigFilePath:      os.Getenv("CONFIG_FILE_PATH"),
+		ConfigProfile:       os.Getenv("CONFIG_PROFILE"),
+		MaxWorkers:          app.getMaxWorkers(),
+		MaxRequests:         app.getMaxRequests(),
+		MaxValidations:      app.getMaxValidations(),
+		HTTPTimeout:         app.getHTTPTimeout(),
+		PublisherTimeout:    app.getPublisherTimeout(),
+		GlobalTimeout:       app.getGlobalTimeout(),
+		TopN:                app.getTopN(),
+		MinSuccessRate:      app.getMinSuccessRate(),
+		HTMLOutputPath:      app.getHTMLOutputPath(),
+		ReportRetention:     app.getReportRetention(),
+		HTMLCompact:         app.getBoolEnv("HTML_COMPACT", false),
+		ErrorsOnly:          app.getBoolEnv("ERRORS_ONLY", false),
+		ReportSort:          os.Getenv("REPORT_SORT"),
+		BestOnly:            app.getBoolEnv("BEST_ONLY", false),
+		JSONOutputPath:      os.Getenv("JSON_OUTPUT_PATH"),
+		JSONOutputDir:       os.Getenv("JSON_OUTPUT_DIR"),
+		JSONLOutputPath:     os.Getenv("JSONL_OUTPUT_PATH"),
+		CSVOutputPath:       os.Getenv("CSV_OUTPUT_PATH"),
+		TSVOutputPath:       os.Getenv("TSV_OUTPUT_PATH"),
+		SQLitePath:          os.Getenv("SQLITE_PATH"),
+		DownloadDir:         os.Getenv("DOWNLOAD_DIR"),
+		CacheDir:            os.Getenv("CACHE_DIR"),
+		CacheTTL:            app.getCacheTTL(),
+		UserAgent:           app.getUserAgent(),
+		ExtraHeaders:        os.Getenv("EXTRA_HEADERS"),
+		ProxyURL:            os.Getenv("PROXY_URL"),
+		InsecureTLS:         app.getBoolEnv("INSECURE_TLS", false),
+		LogLevel:            app.getLogLevel(),
+		DryRun:              app.getBoolEnv("DRY_RUN", false),
+		Bench:               app.getBoolEnv("BENCH", false),
+		Quiet:               app.getBoolEnv("QUIET", false),
+		Verbose:             app.getBoolEnv("VERBOSE", false),
+		MetricsAddr:         os.Getenv("METRICS_ADDR"),
+		CheckpointFile:      os.Getenv("CHECKPOINT_FILE"),
 	}
 
+	app.parseFlags(os.Args[1:])
 	app.validateConfig()
+	utils.UserAgent = app.config.UserAgent
+	if app.config.ExtraHeaders != "" {
+		headers, err := utils.ParseExtraHeaders(app.config.ExtraHeaders)
+		if err != nil {
+			log.Fatalf("❌ Invalid EXTRA_HEADERS: %v", err)
+		}
+		utils.ExtraHeaders = headers
+	}
+	if app.config.ProxyURL != "" {
+		proxyURL, err := utils.ParseProxyURL(app.config.ProxyURL)
+		if err != nil {
+			log.Fatalf("❌ Invalid PROXY_URL: %v", err)
+		}
+		utils.ProxyURL = proxyURL
+	}
+	if app.config.InsecureTLS {
+		log.Println("⚠️ INSECURE_TLS is enabled: requests failing certificate verification are retried WITHOUT verifying the certificate. Only use this for trusted staging or intranet hosts.")
+		utils.InsecureTLS = true
+	}
+	if app.config.JSONLOutputPath == "-" {
+		app.config.Quiet = true // stdout carries the JSON lines
+	}
+	utils.Quiet = app.config.Quiet
+}
+
+// parseFlags overrides the environment configuration with command line
+// flags. Flags default to the values already read from the environment, so
+// only flags that are passed take precedence.
+func (app *LogoCrawlerApp) parseFlags(args []string) {
+	flags := flag.NewFlagSet("logo-crawler", flag.ExitOnError)
+	flags.StringVar(&app.config.PublisherFilePath, "publishers", app.config.PublisherFilePath,
+		"path to the publishers file (env PUBLISHER_FILE_PATH)")
+	flags.StringVar(&app.config.ConfigFilePath, "config", app.config.ConfigFilePath,
+		"path to the YAML config file (env CONFIG_FILE_PATH)")
+	flags.StringVar(&app.config.ConfigProfile, "profile", app.config.ConfigProfile,
+		"named profile in the config file, empty for its default (env CONFIG_PROFILE)")
+	flags.IntVar(&app.config.MaxWorkers, "workers", app.config.MaxWorkers,
+		"number of publishers processed concurrently (env MAX_WORKERS)")
+	flags.StringVar(&app.config.HTMLOutputPath, "html-out", app.config.HTMLOutputPath,
+		"path of the HTML report, empty to skip it (env HTML_OUTPUT_PATH)")
+	flags.BoolVar(&app.config.HTMLCompact, "html-compact", app.config.HTMLCompact,
+		"render the HTML report as a best logo table without images (env HTML_COMPACT)")
+	flags.BoolVar(&app.config.ErrorsOnly, "errors-only", app.config.ErrorsOnly,
+		"list only publishers with errors or no logos in the HTML and JSON reports (env ERRORS_ONLY)")
+	flags.StringVar(&app.config.ReportSort, "sort", app.config.ReportSort,
+		"order of report results: input, duration, success or score (env REPORT_SORT)")
+	flags.BoolVar(&app.config.BestOnly, "best-only", app.config.BestOnly,
+		"list only each publisher's best logo in the reports (env BEST_ONLY)")
+	flags.DurationVar(&app.config.HTTPTimeout, "timeout", app.config.HTTPTimeout,
+		"timeout for each HTTP request, e.g. 15s (env HTTP_TIMEOUT)")
+	flags.Float64Var(&app.config.MinSuccessRate, "min-success-rate", app.config.MinSuccessRate,
+		"exit with code 1 when the success rate (percent) is below this (env MIN_SUCCESS_RATE)")
+	flags.IntVar(&app.config.TopN, "top-n", app.config.TopN,
+		"number of highest scoring logos ranked per publisher (env TOP_N)")
+	flags.BoolVar(&app.config.Bench, "bench", app.config.Bench,
+		"only print throughput metrics (publishers/sec, p50/p95 duration), without reports (env BENCH)")
+	flags.BoolVar(&app.config.DryRun, "dry-run", app.config.DryRun,
+		"only list extracted candidates, without validating them (env DRY_RUN)")
+	flags.BoolVar(&app.config.Quiet, "quiet", app.config.Quiet,
+		"only print the final stats line and report paths (env QUIET)")
+	flags.BoolVar(&app.config.Verbose, "verbose", app.config.Verbose,
+		"print each logo's score and the scoring rules behind it (env VERBOSE)")
+	flags.StringVar(&app.config.MetricsAddr, "metrics-addr", app.config.MetricsAddr,
+		"serve Prometheus metrics on this address, e.g. :9090 (env METRICS_ADDR)")
+	flags.StringVar(&app.config.CheckpointFile, "checkpoint", app.config.CheckpointFile,
+		"record completed publishers here and skip them when rerun (env CHECKPOINT_FILE)")
+	flags.Parse(args)
+
+	if app.config.MaxWorkers <= 0 {
+		log.Fatal("❌ --workers must be greater than 0")
+	}
+	if app.config.HTTPTimeout <= 0 {
+		log.Fatal("❌ --timeout must be greater than 0")
+	}
+	if app.config.TopN <= 0 {
+		log.Fatal("❌ --top-n must be greater than 0")
+	}
+	if app.config.MinSuccessRate < 0 || app.config.MinSuccessRate > 100 {
+		log.Fatal("❌ --min-success-rate must be between 0 and 100")
+	}
+	if app.config.ReportSort != "" && !slices.Contains(output.SortOrders, app.config.ReportSort) {
+		log.Fatalf("❌ --sort must be one of %s", strings.Join(output.SortOrders, ", "))
+	}
 }
 
 // validateConfig validates required configuration
 func (app *LogoCrawlerApp) validateConfig() {
+	if app.config.PublisherFilePath == "" && stdinIsPiped() {
+		app.config.PublisherFilePath = "-" // e.g. cat domains.txt | logo-crawler
+	}
 	if app.config.PublisherFilePath == "" {
-		log.Fatal("❌ Missing PUBLISHER_FILE_PATH env variable")
+		log.Fatal("❌ Missing --publishers flag or PUBLISHER_FILE_PATH env variable (or pipe publishers to stdin)")
 	}
 	if app.config.ConfigFilePath == "" {
-		log.Fatal("❌ Missing CONFIG_FILE_PATH env variable")
+		log.Fatal("❌ Missing --config flag or CONFIG_FILE_PATH env variable")
 	}
 }
 
 // loadConfiguration loads the YAML configuration
 func (app *LogoCrawlerApp) loadConfiguration() {
-	app.prefs = config.LoadConfig(app.config.ConfigFilePath)
+	prefs, err := config.LoadConfig(app.config.ConfigFilePath, app.config.ConfigProfile)
+	if err != nil {
+		log.Fatalf("❌ Failed to load config: %v", err)
+	}
+	app.prefs = prefs
+	app.prefs.Extraction.ProbeWWW = app.getBoolEnv("PROBE_WWW", app.prefs.Extraction.ProbeWWW)
+	app.prefs.Extraction.FollowLinks = app.getBoolEnv("FOLLOW_LINKS", app.prefs.Extraction.FollowLinks)
+	app.prefs.Extraction.AllowHTTP = app.getBoolEnv("ALLOW_HTTP", app.prefs.Extraction.AllowHTTP)
+	app.prefs.Providers.UseClearbit = app.getBoolEnv("USE_CLEARBIT", app.prefs.Providers.UseClearbit)
+
+	if _, err := crawler.WeightsForPreferences(app.prefs); err != nil {
+		log.Fatalf("❌ Invalid config: %v", err)
+	}
 }
 
 // loadPublishers reads publishers from file
@@ -84,70 +377,406 @@ func (app *LogoCrawlerApp) loadPublishers() {
 	loader := utils.NewLoader("Reading publishers from file...")
 	loader.Start()
 
-	var err error
-	app.publishers, err = io.ReadPublishers(app.config.PublisherFilePath)
+	entries, err := app.readPublisherEntries()
 
 	loader.Stop()
 
 	if err != nil {
 		log.Fatalf("Failed to read publishers: %v", err)
 	}
+
+	if app.config.DedupPublishers {
+		var duplicates int
+		entries, duplicates = io.DedupEntries(entries)
+		if duplicates > 0 {
+			app.printf("🧹 Removed %d duplicate publishers (same domain)\n", duplicates)
+		}
+	}
+
+	app.headers = make(map[string]http.Header)
+	app.names = make(map[string]string)
+	for _, entry := range entries {
+		app.publishers = append(app.publishers, entry.Publisher)
+		if len(entry.Headers) > 0 {
+			app.headers[entry.Publisher] = entry.Headers
+		}
+		if entry.DisplayName != "" {
+			app.names[entry.Publisher] = entry.DisplayName
+		}
+	}
 	if len(app.publishers) == 0 {
 		log.Fatal("❌ No publishers found in file")
 	}
 
-	fmt.Printf("✅ Loaded %d publishers\n", len(app.publishers))
+	app.printf("✅ Loaded %d publishers\n", len(app.publishers))
+}
+
+// readPublisherEntries reads the publishers file, or stdin when the path is
+// "-", as CSV when a column is configured
+func (app *LogoCrawlerApp) readPublisherEntries() ([]io.PublisherEntry, error) {
+	if app.config.PublisherFilePath == "-" {
+		if app.config.PublisherColumn == "" {
+			return io.ReadPublisherEntriesFromReader(os.Stdin)
+		}
+		return io.ReadPublisherEntriesCSVFromReader(os.Stdin, app.config.PublisherColumn, app.config.PublisherNameColumn)
+	}
+
+	if app.config.PublisherColumn == "" {
+		return io.ReadPublisherEntries(app.config.PublisherFilePath)
+	}
+
+	return io.ReadPublisherEntriesCSV(app.config.PublisherFilePath, app.config.PublisherColumn, app.config.PublisherNameColumn)
+}
+
+// stdinIsPiped reports whether stdin is a pipe or file rather than a terminal
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
 }
 
 // displayStartupInfo shows startup information
 func (app *LogoCrawlerApp) displayStartupInfo() {
-	fmt.Printf("🚀 Starting concurrent logo crawler with %d workers for %d publishers\n",
+	app.printf("🚀 Starting concurrent logo crawler with %d workers for %d publishers\n",
 		app.config.MaxWorkers, len(app.publishers))
-	fmt.Printf("⚡ Using %d CPU cores\n", runtime.NumCPU())
+	app.printf("⚡ Using %d CPU cores\n", runtime.NumCPU())
+	if app.config.ConfigProfile != "" {
+		app.printf("🗂️  Using config profile %q\n", app.config.ConfigProfile)
+	}
+	if app.config.DryRun {
+		app.println("🧪 Dry run: listing candidates without validating them")
+	}
 }
 
 // processPublishers processes all publishers concurrently
-func (app *LogoCrawlerApp) processPublishers() ([]crawler.PublisherResult, time.Duration) {
-	fmt.Println("\n🔄 Starting logo crawling process...")
+func (app *LogoCrawlerApp) processPublishers(ctx context.Context) ([]crawler.PublisherResult, time.Duration) {
+	app.println("\n🔄 Starting logo crawling process...")
+
+	restored := app.openCheckpoint()
 
 	// Create progress bar for overall progress
 	progressBar := utils.NewProgressBar(len(app.publishers), "Processing publishers")
+	progressBar.Update(len(restored))
+
+	opts := app.crawlOptions(ctx, progressBar)
 
 	start := time.Now()
-	results := crawler.FetchPublishersConcurrently(app.publishers, app.prefs, app.config.MaxWorkers)
+	results := crawler.FetchPublishersConcurrently(ctx, app.pendingPublishers(restored), app.prefs, opts)
 	totalDuration := time.Since(start)
+	results = app.withRestored(restored, results)
 
 	progressBar.Complete()
-
-	fmt.Printf("\n📊 Results Summary:\n")
-	fmt.Printf("⏱️  Total time: %v\n", totalDuration)
-	fmt.Printf("📈 Average time per publisher: %v\n", totalDuration/time.Duration(len(app.publishers)))
+	app.displaySummary(totalDuration, opts)
+	app.closeCheckpoint(ctx, slices.ContainsFunc(results, func(result crawler.PublisherResult) bool {
+		return result.Skipped
+	}))
 
 	return results, totalDuration
 }
 
+// streamPublishers processes all publishers concurrently, writing each result
+// as a JSON line as soon as it completes rather than keeping it in memory
+func (app *LogoCrawlerApp) streamPublishers(ctx context.Context) Stats {
+	app.println("\n🔄 Starting logo crawling process (streaming JSON lines)...")
+
+	out := os.Stdout
+	if app.config.JSONLOutputPath != "-" {
+		if err := os.MkdirAll(filepath.Dir(app.config.JSONLOutputPath), 0755); err != nil {
+			log.Fatalf("❌ Failed to create JSONL output directory: %v", err)
+		}
+		file, err := os.Create(app.config.JSONLOutputPath)
+		if err != nil {
+			log.Fatalf("❌ Failed to create JSONL output: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	writer := output.NewJSONLWriter(out)
+
+	restored := app.openCheckpoint()
+	progressBar := utils.NewProgressBar(len(app.publishers), "Processing publishers")
+	progressBar.Update(len(restored))
+	opts := app.crawlOptions(ctx, progressBar)
+
+	stats := Stats{TotalPublishers: len(app.publishers)}
+	skipped := false
+	write := func(result crawler.PublisherResult) {
+		stats.add(result)
+		skipped = skipped || result.Skipped
+		if app.config.BestOnly {
+			result = output.TrimToBest(result)
+		}
+		if err := writer.Write(result); err != nil {
+			log.Printf("⚠️ %s: %v", result.Publisher, err)
+		}
+	}
+	for _, result := range restored {
+		write(result)
+	}
+	start := time.Now()
+	for result := range crawler.Stream(ctx, app.pendingPublishers(restored), app.prefs, opts) {
+		write(result)
+	}
+	totalDuration := time.Since(start)
+
+	progressBar.Complete()
+	app.displaySummary(totalDuration, opts)
+	app.closeCheckpoint(ctx, skipped)
+
+	stats.TotalBytes = app.bytes.Total()
+	stats.finish()
+	return stats
+}
+
+// displaySummary prints the run's timing and request usage
+func (app *LogoCrawlerApp) displaySummary(totalDuration time.Duration, opts crawler.Options) {
+	app.printf("\n📊 Results Summary:\n")
+	app.printf("⏱️  Total time: %v\n", totalDuration)
+	app.printf("📈 Average time per publisher: %v\n", totalDuration/time.Duration(len(app.publishers)))
+	if opts.Budget != nil {
+		app.printf("🌐 Requests used: %d/%d\n", opts.Budget.Used(), opts.Budget.Limit())
+	}
+}
+
+// crawlOptions builds the crawler options for this run, driving progressBar
+// and recording results to the checkpoint
+func (app *LogoCrawlerApp) crawlOptions(ctx context.Context, progressBar *utils.ProgressBar) crawler.Options {
+	app.bytes = utils.NewByteCounter()
+	opts := crawler.Options{
+		MaxWorkers:       app.config.MaxWorkers,
+		MaxValidations:   app.config.MaxValidations,
+		RequestTimeout:   app.config.HTTPTimeout,
+		PublisherTimeout: app.config.PublisherTimeout,
+		TopN:             app.config.TopN,
+		Headers:          app.headers,
+		DisplayNames:     app.names,
+		Logger:           app.newLogger(),
+		DryRun:           app.config.DryRun,
+		ShutdownGrace:    shutdownGrace,
+		Bytes:            app.bytes,
+		OnResult: func(result crawler.PublisherResult) {
+			progressBar.Increment()
+			app.metrics.Observe(result)
+			app.recordCheckpoint(ctx, result)
+		},
+	}
+	if app.config.MaxRequests > 0 {
+		opts.Budget = utils.NewRequestBudget(app.config.MaxRequests)
+	}
+	if app.config.CacheDir != "" {
+		cache, err := crawler.NewValidationCache(app.config.CacheDir, app.config.CacheTTL)
+		if err != nil {
+			log.Printf("⚠️ Validation cache disabled: %v", err)
+		} else {
+			opts.Cache = cache
+		}
+	}
+	opts.Client = crawler.NewRunClient(app.prefs, opts)
+	app.client = opts.Client
+	return opts
+}
+
+// openCheckpoint opens CheckpointFile and returns the results it holds from
+// an earlier, interrupted run. It does nothing without CheckpointFile or in
+// dry-run mode, whose results are not worth keeping.
+func (app *LogoCrawlerApp) openCheckpoint() []crawler.PublisherResult {
+	if app.config.CheckpointFile == "" || app.config.DryRun {
+		return nil
+	}
+
+	checkpoint, restored, err := output.OpenCheckpoint(app.config.CheckpointFile)
+	if err != nil {
+		log.Fatalf("❌ Failed to open checkpoint: %v", err)
+	}
+	app.checkpoint = checkpoint
+	if len(restored) > 0 {
+		app.printf("♻️  Resuming from %s: %d publishers already done\n", app.config.CheckpointFile, len(restored))
+	}
+	return restored
+}
+
+// pendingPublishers returns the publishers without a restored result, in
+// input order. A publisher listed twice needs two restored results to be
+// skipped twice.
+func (app *LogoCrawlerApp) pendingPublishers(restored []crawler.PublisherResult) []string {
+	if len(restored) == 0 {
+		return app.publishers
+	}
+
+	done := make(map[string]int, len(restored))
+	for _, result := range restored {
+		done[result.Publisher]++
+	}
+	pending := make([]string, 0, len(app.publishers))
+	for _, publisher := range app.publishers {
+		if done[publisher] > 0 {
+			done[publisher]--
+			continue
+		}
+		pending = append(pending, publisher)
+	}
+	return pending
+}
+
+// withRestored merges restored results with those of this run, setting each
+// result's Index to its publisher's position in the input
+func (app *LogoCrawlerApp) withRestored(restored, results []crawler.PublisherResult) []crawler.PublisherResult {
+	if len(restored) == 0 {
+		return results
+	}
+
+	byPublisher := make(map[string][]crawler.PublisherResult, len(restored)+len(results))
+	for _, result := range slices.Concat(restored, results) {
+		byPublisher[result.Publisher] = append(byPublisher[result.Publisher], result)
+	}
+	merged := make([]crawler.PublisherResult, 0, len(app.publishers))
+	for index, publisher := range app.publishers {
+		queue := byPublisher[publisher]
+		if len(queue) == 0 {
+			continue
+		}
+		result := queue[0]
+		byPublisher[publisher] = queue[1:]
+		result.Index = index
+		merged = append(merged, result)
+	}
+	return merged
+}
+
+// recordCheckpoint appends a finished publisher to the checkpoint. Skipped
+// publishers and those failed by the run being stopped are left out so a
+// rerun processes them.
+func (app *LogoCrawlerApp) recordCheckpoint(ctx context.Context, result crawler.PublisherResult) {
+	if app.checkpoint == nil || result.Skipped || (result.Error != nil && ctx.Err() != nil) {
+		return
+	}
+	if err := app.checkpoint.Record(result); err != nil {
+		log.Printf("⚠️ Failed to write checkpoint: %v", err)
+	}
+}
+
+// closeCheckpoint deletes the checkpoint once every publisher is done, and
+// otherwise keeps it for the next run
+func (app *LogoCrawlerApp) closeCheckpoint(ctx context.Context, skipped bool) {
+	if app.checkpoint == nil {
+		return
+	}
+
+	if ctx.Err() != nil || skipped {
+		app.checkpoint.Close()
+		app.printf("💾 Progress saved to %s: rerun to resume\n", app.config.CheckpointFile)
+		return
+	}
+	if err := app.checkpoint.Remove(); err != nil {
+		log.Printf("⚠️ Failed to remove checkpoint: %v", err)
+	}
+}
+
+// startMetricsServer serves Prometheus metrics at /metrics on MetricsAddr
+// until the returned function is called. It does nothing when MetricsAddr is
+// empty. Run stops the server when it returns, once reports are written, so
+// the process does not linger: counters updated after the last scrape are
+// never seen by Prometheus, and runs shorter than the scrape interval may
+// not be scraped at all. The reports hold the final numbers.
+func (app *LogoCrawlerApp) startMetricsServer() (stop func()) {
+	if app.config.MetricsAddr == "" {
+		return func() {}
+	}
+
+	// Listen first so a bad or busy address fails the run right away
+	listener, err := net.Listen("tcp", app.config.MetricsAddr)
+	if err != nil {
+		log.Fatalf("❌ Failed to start metrics server: %v", err)
+	}
+
+	app.metrics = output.NewMetrics()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", app.metrics)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("⚠️ Metrics server stopped: %v", err)
+		}
+	}()
+
+	app.printf("📊 Metrics served at http://%s/metrics\n", listener.Addr())
+	return func() { server.Close() }
+}
+
+// displayBenchmark prints throughput metrics for capacity planning: the
+// publishers processed per second of wall time and the per-publisher
+// duration percentiles. Skipped publishers are left out.
+func (app *LogoCrawlerApp) displayBenchmark(results []crawler.PublisherResult, totalDuration time.Duration) {
+	durations := make([]time.Duration, 0, len(results))
+	for _, result := range results {
+		if !result.Skipped {
+			durations = append(durations, result.Duration)
+		}
+	}
+	throughput := float64(len(durations)) / totalDuration.Seconds()
+	p := utils.Percentiles(durations, 50, 95)
+	for i := range p {
+		p[i] = p[i].Round(time.Millisecond)
+	}
+	totalDuration = totalDuration.Round(time.Millisecond)
+
+	if app.config.Quiet {
+		fmt.Printf("workers=%d publishers=%d total=%v publishers_per_sec=%.2f p50=%v p95=%v\n",
+			app.config.MaxWorkers, len(durations), totalDuration, throughput, p[0], p[1])
+		return
+	}
+
+	fmt.Printf("\n🏁 Benchmark:\n")
+	fmt.Printf("   Workers: %d\n", app.config.MaxWorkers)
+	fmt.Printf("   Publishers processed: %d in %v\n", len(durations), totalDuration)
+	fmt.Printf("   Throughput: %.2f publishers/sec\n", throughput)
+	fmt.Printf("   Per-publisher duration: p50 %v, p95 %v\n", p[0], p[1])
+}
+
 // displayResults displays the processing results
 func (app *LogoCrawlerApp) displayResults(results []crawler.PublisherResult) {
 	stats := app.calculateStats(results)
 
-	for _, result := range results {
-		app.displayPublisherResult(result)
+	if !app.config.Quiet {
+		for _, result := range results {
+			app.displayPublisherResult(result)
+		}
 	}
 
+	if app.config.DryRun {
+		return // Logo stats are meaningless without validation
+	}
 	app.displayFinalStats(stats)
 }
 
 // displayPublisherResult displays result for a single publisher
 func (app *LogoCrawlerApp) displayPublisherResult(result crawler.PublisherResult) {
+	if result.Skipped {
+		app.printf("\n⏭️  Publisher: %s - SKIPPED: %v\n", result.Publisher, result.Error)
+		return
+	}
 	if result.Error != nil {
-		fmt.Printf("\n❌ Publisher: %s (processed in %v) - ERROR: %v\n",
+		app.printf("\n❌ Publisher: %s (processed in %v) - ERROR: %v\n",
 			result.Publisher, result.Duration, result.Error)
 		return
 	}
 
-	fmt.Printf("\n🔎 Publisher: %s (processed in %v)\n", result.Publisher, result.Duration)
+	app.printf("\n🔎 Publisher: %s (processed in %v: extract %v, validate %v)\n",
+		result.Name(), result.Duration, result.ExtractDuration, result.ValidateDuration)
+	if result.ResolvedDomain != "" {
+		app.printf("   ↪ Redirected to %s\n", result.ResolvedDomain)
+	}
+	if app.config.DryRun {
+		app.printf("   %d candidates (not validated):\n", len(result.Candidates))
+		for _, candidate := range result.Candidates {
+			app.printf("   [%s] %s\n", candidate.Source, utils.ShortURL(candidate.URL))
+		}
+		return
+	}
 	if len(result.Logos) == 0 {
-		fmt.Println("❌ No valid logos found")
+		app.println("❌ No valid logos found")
+		for _, rejected := range result.Rejected {
+			app.printf("   ✗ %s - %s\n", utils.ShortURL(rejected.URL), rejected.Reason)
+		}
 		return
 	}
 
@@ -156,8 +785,27 @@ func (app *LogoCrawlerApp) displayPublisherResult(result crawler.PublisherResult
 		if result.Best != nil && logo.URL == result.Best.URL {
 			mark = " <- ✅ SUGGESTED"
 		}
-		fmt.Printf("   %s (%dx%d)%s\n", logo.URL, logo.Width, logo.Height, mark)
+		app.printf("   [%s] %s (%dx%d)%s\n", logo.Source, utils.ShortURL(logo.URL), logo.Width, logo.Height, mark)
+		if app.config.Verbose {
+			app.printf("      🧮 score %d: %s\n", logo.Score, formatBreakdown(logo.Breakdown))
+		}
+		for _, warning := range logo.Warnings {
+			app.printf("      ⚠️ %s\n", warning)
+		}
+	}
+}
+
+// formatBreakdown lists scoring rule contributions, e.g. "+15 clearbit, -40
+// partner"
+func formatBreakdown(breakdown []crawler.RuleScore) string {
+	if len(breakdown) == 0 {
+		return "no rules applied"
+	}
+	parts := make([]string, len(breakdown))
+	for i, rule := range breakdown {
+		parts[i] = fmt.Sprintf("%+d %s", rule.Points, rule.Rule)
 	}
+	return strings.Join(parts, ", ")
 }
 
 // Stats holds processing statistics
@@ -165,38 +813,78 @@ type Stats struct {
 	TotalPublishers int
 	ValidPublishers int
 	ErrorCount      int
+	SkippedCount    int
 	TotalLogos      int
 	SuccessRate     float64
+	TotalBytes      int64         // Response body bytes downloaded
+	P50, P90, P99   time.Duration // Per-publisher duration percentiles
+
+	durations []time.Duration
 }
 
 // calculateStats calculates processing statistics
 func (app *LogoCrawlerApp) calculateStats(results []crawler.PublisherResult) Stats {
-	stats := Stats{TotalPublishers: len(app.publishers)}
-
+	stats := Stats{TotalPublishers: len(app.publishers), TotalBytes: app.bytes.Total()}
 	for _, result := range results {
-		if result.Error != nil {
-			stats.ErrorCount++
-			continue
-		}
+		stats.add(result)
+	}
+	stats.finish()
+	return stats
+}
 
-		stats.TotalLogos += len(result.Logos)
-		if len(result.Logos) > 0 {
-			stats.ValidPublishers++
-		}
+// add counts one publisher result
+func (s *Stats) add(result crawler.PublisherResult) {
+	if result.Skipped {
+		s.SkippedCount++
+	} else {
+		s.durations = append(s.durations, result.Duration)
+	}
+	if result.Error != nil {
+		s.ErrorCount++
+		return
 	}
 
-	stats.SuccessRate = float64(stats.ValidPublishers) / float64(stats.TotalPublishers) * 100
-	return stats
+	s.TotalLogos += len(result.Logos)
+	if len(result.Logos) > 0 {
+		s.ValidPublishers++
+	}
+}
+
+// finish computes the success rate and duration percentiles once all
+// results are counted
+func (s *Stats) finish() {
+	s.SuccessRate = float64(s.ValidPublishers) / float64(s.TotalPublishers) * 100
+	p := utils.Percentiles(s.durations, 50, 90, 99)
+	s.P50, s.P90, s.P99 = p[0], p[1], p[2]
+}
+
+// statsLine formats stats as a single key=value line
+func statsLine(stats Stats) string {
+	return fmt.Sprintf("publishers=%d with_logos=%d errors=%d skipped=%d logos=%d success_rate=%.1f%% bytes=%d p50_ms=%d p90_ms=%d p99_ms=%d",
+		stats.TotalPublishers, stats.ValidPublishers, stats.ErrorCount, stats.SkippedCount,
+		stats.TotalLogos, stats.SuccessRate, stats.TotalBytes,
+		stats.P50.Milliseconds(), stats.P90.Milliseconds(), stats.P99.Milliseconds())
 }
 
 // displayFinalStats displays final processing statistics
 func (app *LogoCrawlerApp) displayFinalStats(stats Stats) {
+	if app.config.Quiet {
+		fmt.Println(statsLine(stats))
+		return
+	}
+
 	fmt.Printf("\n📈 Final Stats:\n")
 	fmt.Printf("   Total publishers: %d\n", stats.TotalPublishers)
 	fmt.Printf("   Publishers with logos: %d\n", stats.ValidPublishers)
 	fmt.Printf("   Publishers with errors: %d\n", stats.ErrorCount)
+	if stats.SkippedCount > 0 {
+		fmt.Printf("   Publishers skipped: %d\n", stats.SkippedCount)
+	}
 	fmt.Printf("   Total logos found: %d\n", stats.TotalLogos)
 	fmt.Printf("   Success rate: %.1f%%\n", stats.SuccessRate)
+	fmt.Printf("   Data downloaded: %s\n", utils.FormatBytes(stats.TotalBytes))
+	fmt.Printf("   Publisher time: p50 %v, p90 %v, p99 %v\n",
+		stats.P50.Round(time.Millisecond), stats.P90.Round(time.Millisecond), stats.P99.Round(time.Millisecond))
 }
 
 // generateHTMLReport generates an HTML report
@@ -209,6 +897,9 @@ func (app *LogoCrawlerApp) generateHTMLReport(results []crawler.PublisherResult,
 	loader.Start()
 
 	generator := output.NewHTMLGenerator(app.config.HTMLOutputPath)
+	generator.Compact = app.config.HTMLCompact
+	generator.ErrorsOnly = app.config.ErrorsOnly
+	generator.TotalBytes = app.bytes.Total()
 	if err := generator.GenerateReport(results, totalDuration); err != nil {
 		loader.Stop()
 		log.Printf("⚠️ Failed to generate HTML report: %v", err)
@@ -217,13 +908,135 @@ func (app *LogoCrawlerApp) generateHTMLReport(results []crawler.PublisherResult,
 
 	loader.Stop()
 	fmt.Printf("📄 HTML report generated: %s\n", app.config.HTMLOutputPath)
+	app.pruneReports()
 
 	// Open the report in the default browser
 	if err := utils.OpenHTMLFile(app.config.HTMLOutputPath); err != nil {
 		log.Printf("⚠️ Failed to open browser: %v", err)
-		fmt.Printf("💡 You can manually open the report at: %s\n", app.config.HTMLOutputPath)
+		app.printf("💡 You can manually open the report at: %s\n", app.config.HTMLOutputPath)
 	} else {
-		fmt.Printf("🌐 Opening report in default browser...\n")
+		app.printf("🌐 Opening report in default browser...\n")
+	}
+}
+
+// pruneReports applies REPORT_RETENTION to the HTML report's directory
+func (app *LogoCrawlerApp) pruneReports() {
+	if app.config.ReportRetention <= 0 {
+		return
+	}
+
+	dir := filepath.Dir(app.config.HTMLOutputPath)
+	deleted, err := output.PruneReports(dir, app.config.ReportRetention)
+	if err != nil {
+		log.Printf("⚠️ Failed to prune old reports: %v", err)
+	}
+	if deleted > 0 {
+		app.printf("🧹 Removed %d old report(s) from %s\n", deleted, dir)
+	}
+}
+
+// generateJSONReport writes all results to a single JSON file
+func (app *LogoCrawlerApp) generateJSONReport(results []crawler.PublisherResult, totalDuration time.Duration) {
+	if app.config.JSONOutputPath == "" {
+		return
+	}
+
+	generator := output.NewJSONGenerator(app.config.JSONOutputPath)
+	generator.ErrorsOnly = app.config.ErrorsOnly
+	if err := generator.GenerateReport(results, totalDuration); err != nil {
+		log.Printf("⚠️ Failed to generate JSON report: %v", err)
+		return
+	}
+	fmt.Printf("📄 JSON report generated: %s\n", app.config.JSONOutputPath)
+}
+
+// generateJSONFiles writes one JSON file per publisher
+func (app *LogoCrawlerApp) generateJSONFiles(results []crawler.PublisherResult) {
+	if app.config.JSONOutputDir == "" {
+		return
+	}
+
+	generator := output.NewJSONDirGenerator(app.config.JSONOutputDir)
+	written, err := generator.GenerateFiles(results)
+	if err != nil {
+		log.Printf("⚠️ Failed to write some JSON files: %v", err)
+	}
+	fmt.Printf("📁 %d JSON files written to %s\n", written, app.config.JSONOutputDir)
+}
+
+// generateSQLiteReport appends the run to the SQLite database
+func (app *LogoCrawlerApp) generateSQLiteReport(results []crawler.PublisherResult, totalDuration time.Duration) {
+	if app.config.SQLitePath == "" {
+		return
+	}
+
+	generator := output.NewSQLiteGenerator(app.config.SQLitePath)
+	if err := generator.GenerateReport(results, totalDuration); err != nil {
+		log.Printf("⚠️ Failed to write SQLite report: %v", err)
+		return
+	}
+	fmt.Printf("🗄️ SQLite report written: %s\n", app.config.SQLitePath)
+}
+
+// generateCSVReport writes one CSV row per publisher
+func (app *LogoCrawlerApp) generateCSVReport(results []crawler.PublisherResult) {
+	if app.config.CSVOutputPath == "" {
+		return
+	}
+
+	generator := output.NewCSVGenerator(app.config.CSVOutputPath)
+	if err := generator.GenerateReport(results); err != nil {
+		log.Printf("⚠️ Failed to generate CSV report: %v", err)
+		return
+	}
+	fmt.Printf("📄 CSV report generated: %s\n", app.config.CSVOutputPath)
+}
+
+// generateTSVReport writes the CSV report's rows tab-separated
+func (app *LogoCrawlerApp) generateTSVReport(results []crawler.PublisherResult) {
+	if app.config.TSVOutputPath == "" {
+		return
+	}
+
+	generator := output.NewTSVGenerator(app.config.TSVOutputPath)
+	if err := generator.GenerateReport(results); err != nil {
+		log.Printf("⚠️ Failed to generate TSV report: %v", err)
+		return
+	}
+	fmt.Printf("📄 TSV report generated: %s\n", app.config.TSVOutputPath)
+}
+
+// downloadLogos saves each publisher's best logo to the download directory
+func (app *LogoCrawlerApp) downloadLogos(results []crawler.PublisherResult) {
+	if app.config.DownloadDir == "" {
+		return
+	}
+
+	loader := utils.NewLoader("Downloading best logos...")
+	loader.Start()
+
+	downloader := output.NewImageDownloader(app.config.DownloadDir, app.client, app.prefs.Validation.MaxReadBytes)
+	err := downloader.Download(results)
+
+	loader.Stop()
+
+	if err != nil {
+		log.Printf("⚠️ Failed to download some logos: %v", err)
+	}
+	fmt.Printf("🖼️  Best logos saved to: %s\n", app.config.DownloadDir)
+}
+
+// printf prints decorative console output, suppressed in quiet mode
+func (app *LogoCrawlerApp) printf(format string, args ...any) {
+	if !app.config.Quiet {
+		fmt.Printf(format, args...)
+	}
+}
+
+// println prints decorative console output, suppressed in quiet mode
+func (app *LogoCrawlerApp) println(args ...any) {
+	if !app.config.Quiet {
+		fmt.Println(args...)
 	}
 }
 
@@ -243,6 +1056,149 @@ func (app *LogoCrawlerApp) getMaxWorkers() int {
 	return maxWorkers
 }
 
+// getMaxValidations returns the concurrent validations per publisher from
+// MAX_VALIDATIONS
+func (app *LogoCrawlerApp) getMaxValidations() int {
+	if maxValidationsStr := os.Getenv("MAX_VALIDATIONS"); maxValidationsStr != "" {
+		if maxValidations, err := strconv.Atoi(maxValidationsStr); err == nil && maxValidations > 0 {
+			return maxValidations
+		}
+		log.Printf("⚠️ Invalid MAX_VALIDATIONS %q, using default %d", maxValidationsStr, crawler.DefaultMaxValidations)
+	}
+	return crawler.DefaultMaxValidations
+}
+
+// getMaxRequests returns the request budget for the run (0 means unlimited)
+func (app *LogoCrawlerApp) getMaxRequests() int {
+	if maxRequestsStr := os.Getenv("MAX_REQUESTS"); maxRequestsStr != "" {
+		if maxRequests, err := strconv.Atoi(maxRequestsStr); err == nil && maxRequests > 0 {
+			return maxRequests
+		}
+		log.Printf("⚠️ Invalid MAX_REQUESTS %q, running without a request budget", maxRequestsStr)
+	}
+	return 0
+}
+
+// getReportRetention returns how many timestamped HTML reports to keep from
+// REPORT_RETENTION (0 keeps all)
+func (app *LogoCrawlerApp) getReportRetention() int {
+	if retentionStr := os.Getenv("REPORT_RETENTION"); retentionStr != "" {
+		if retention, err := strconv.Atoi(retentionStr); err == nil && retention >= 0 {
+			return retention
+		}
+		log.Printf("⚠️ Invalid REPORT_RETENTION %q, keeping all reports", retentionStr)
+	}
+	return 0
+}
+
+// getTopN returns the number of ranked logos per publisher from TOP_N
+func (app *LogoCrawlerApp) getTopN() int {
+	if topNStr := os.Getenv("TOP_N"); topNStr != "" {
+		if topN, err := strconv.Atoi(topNStr); err == nil && topN > 0 {
+			return topN
+		}
+		log.Printf("⚠️ Invalid TOP_N %q, using default %d", topNStr, crawler.DefaultTopN)
+	}
+	return crawler.DefaultTopN
+}
+
+// getHTTPTimeout returns the per-request timeout from HTTP_TIMEOUT (e.g. "15s")
+func (app *LogoCrawlerApp) getHTTPTimeout() time.Duration {
+	if timeoutStr := os.Getenv("HTTP_TIMEOUT"); timeoutStr != "" {
+		if timeout, err := time.ParseDuration(timeoutStr); err == nil && timeout > 0 {
+			return timeout
+		}
+		log.Printf("⚠️ Invalid HTTP_TIMEOUT %q, using default %v", timeoutStr, utils.DefaultTimeout)
+	}
+	return utils.DefaultTimeout
+}
+
+// getPublisherTimeout returns the overall budget per publisher from PUBLISHER_TIMEOUT
+func (app *LogoCrawlerApp) getPublisherTimeout() time.Duration {
+	if timeoutStr := os.Getenv("PUBLISHER_TIMEOUT"); timeoutStr != "" {
+		if timeout, err := time.ParseDuration(timeoutStr); err == nil && timeout > 0 {
+			return timeout
+		}
+		log.Printf("⚠️ Invalid PUBLISHER_TIMEOUT %q, using default %v", timeoutStr, crawler.DefaultPublisherTimeout)
+	}
+	return crawler.DefaultPublisherTimeout
+}
+
+// getGlobalTimeout returns the deadline for the whole run from GLOBAL_TIMEOUT
+// (e.g. "2h"), or 0 when the run is unbounded
+func (app *LogoCrawlerApp) getGlobalTimeout() time.Duration {
+	if timeoutStr := os.Getenv("GLOBAL_TIMEOUT"); timeoutStr != "" {
+		if timeout, err := time.ParseDuration(timeoutStr); err == nil && timeout > 0 {
+			return timeout
+		}
+		log.Printf("⚠️ Invalid GLOBAL_TIMEOUT %q, running without a global deadline", timeoutStr)
+	}
+	return 0
+}
+
+// getMinSuccessRate returns the success rate percentage required for a zero
+// exit code from MIN_SUCCESS_RATE (0 disables the check)
+func (app *LogoCrawlerApp) getMinSuccessRate() float64 {
+	if rateStr := os.Getenv("MIN_SUCCESS_RATE"); rateStr != "" {
+		if rate, err := strconv.ParseFloat(rateStr, 64); err == nil && rate >= 0 && rate <= 100 {
+			return rate
+		}
+		log.Printf("⚠️ Invalid MIN_SUCCESS_RATE %q, not enforcing a minimum", rateStr)
+	}
+	return 0
+}
+
+// getCacheTTL returns how long cached validation results stay fresh from CACHE_TTL
+func (app *LogoCrawlerApp) getCacheTTL() time.Duration {
+	if ttlStr := os.Getenv("CACHE_TTL"); ttlStr != "" {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil && ttl > 0 {
+			return ttl
+		}
+		log.Printf("⚠️ Invalid CACHE_TTL %q, using default %v", ttlStr, crawler.DefaultCacheTTL)
+	}
+	return crawler.DefaultCacheTTL
+}
+
+// getUserAgent returns the User-Agent sent with outbound requests
+func (app *LogoCrawlerApp) getUserAgent() string {
+	if userAgent := os.Getenv("USER_AGENT"); userAgent != "" {
+		return userAgent
+	}
+	return utils.DefaultUserAgent
+}
+
+// getBoolEnv parses a boolean environment variable, returning fallback when
+// it is unset or invalid
+func (app *LogoCrawlerApp) getBoolEnv(name string, fallback bool) bool {
+	if valueStr := os.Getenv(name); valueStr != "" {
+		if value, err := strconv.ParseBool(valueStr); err == nil {
+			return value
+		}
+		log.Printf("⚠️ Invalid %s %q, using default %v", name, valueStr, fallback)
+	}
+	return fallback
+}
+
+// getLogLevel returns the level of the crawler's structured logs from
+// LOG_LEVEL (debug, info, warn or error). It defaults to warn so the console
+// output stays readable.
+func (app *LogoCrawlerApp) getLogLevel() slog.Level {
+	level := slog.LevelWarn
+	if levelStr := os.Getenv("LOG_LEVEL"); levelStr != "" {
+		if err := level.UnmarshalText([]byte(levelStr)); err != nil {
+			log.Printf("⚠️ Invalid LOG_LEVEL %q, using default %v", levelStr, slog.LevelWarn)
+			return slog.LevelWarn
+		}
+	}
+	return level
+}
+
+// newLogger creates the structured logger for crawler diagnostics, writing
+// to stderr so it stays apart from the console report
+func (app *LogoCrawlerApp) newLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: app.config.LogLevel}))
+}
+
 // getHTMLOutputPath gets the HTML output path from environment or uses default
 func (app *LogoCrawlerApp) getHTMLOutputPath() string {
 	if path := os.Getenv("HTML_OUTPUT_PATH"); path != "" {
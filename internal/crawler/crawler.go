@@ -1,28 +1,162 @@
 package crawler
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/Tanmay-Thanvi/logo-crawler/config"
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
 )
 
 type LogoInfo struct {
 	URL    string
 	Width  int
 	Height int
+	Format string // Decoded format: png, jpeg, gif, webp, ico or svg
 	Valid  bool
+
+	AspectRatio float64 // Width divided by height
+	HasAlpha    bool    // Transparency detected (PNG and WebP only)
+	Background  string  // Corner color: light, dark or transparent; "" if unknown
+
+	Source         string   // Where the logo was discovered (see Source* constants)
+	Position       int      // Index among logos of the same source, in document order
+	DeclaredWidth  int      // Width declared by the page, 0 if unknown
+	DeclaredHeight int      // Height declared by the page, 0 if unknown
+	Scalable       bool     // Declared with sizes="any"
+	Alt            string   // Alt text of the img tag the logo came from
+	Warnings       []string // Non-fatal issues found during validation
+	ContentHash    string   // Hex SHA-256 of the image body, used to drop duplicates
+
+	Score     int         // Selection score assigned by BestLogoSelector
+	Breakdown []RuleScore // Scoring rules that make up Score, in the order applied
 }
 
 type PublisherResult struct {
 	Publisher string
-	Logos     []LogoInfo
-	Best      *LogoInfo
-	Error     error
-	Duration  time.Duration
-	Index     int // To preserve input order
+	// DisplayName is the friendly name given with the input, if any (see
+	// Name)
+	DisplayName string
+	Logos       []LogoInfo
+	Best        *LogoInfo
+	// ResolvedDomain is the domain the publisher's homepage redirected to,
+	// empty when it did not leave the input domain
+	ResolvedDomain string
+	// TopN holds the highest scoring logos, best first (see Options.TopN)
+	TopN     []LogoInfo
+	Error    error
+	Duration time.Duration
+	// ExtractDuration and ValidateDuration split Duration into candidate
+	// extraction and validation; ValidateDuration is 0 in dry-run mode
+	ExtractDuration  time.Duration
+	ValidateDuration time.Duration
+	Index            int  // To preserve input order
+	Skipped          bool // Not processed because the request budget ran out
+	// Candidates lists every extracted candidate; only set in dry-run mode,
+	// where Logos and Best stay empty
+	Candidates []Candidate
+	// Rejected lists candidates that failed validation, with the reason
+	Rejected []RejectedCandidate
+}
+
+// Name returns the display name, or the publisher as given when it has none
+func (r PublisherResult) Name() string {
+	if r.DisplayName != "" {
+		return r.DisplayName
+	}
+	return r.Publisher
+}
+
+// Default values used for zero Options fields
+const (
+	DefaultMaxWorkers        = 5
+	DefaultValidationTimeout = 30 * time.Second
+	DefaultPublisherTimeout  = 45 * time.Second
+	DefaultTopN              = 1
+	DefaultMaxValidations    = 10
+)
+
+// Options configures a concurrent crawl run
+type Options struct {
+	MaxWorkers int // Publishers processed concurrently (default 5)
+	// RequestTimeout bounds each HTTP request (default: the shared client's 8s)
+	RequestTimeout time.Duration
+	// MaxValidations caps concurrent candidate validations per publisher (default 10)
+	MaxValidations int
+	// ValidationTimeout bounds validating all candidates of one publisher (default 30s)
+	ValidationTimeout time.Duration
+	// PublisherTimeout bounds all work for one publisher (default 45s)
+	PublisherTimeout time.Duration
+	// TopN is the number of highest scoring logos kept in
+	// PublisherResult.TopN (default 1)
+	TopN int
+	// Budget optionally caps the total number of outbound requests. Once it
+	// is exhausted no new requests are issued and remaining publishers are
+	// marked as skipped.
+	Budget *utils.RequestBudget
+	// Client optionally is the HTTP client shared by all publishers. Stream
+	// builds one with NewRunClient when it is nil; setting it lets the
+	// caller reuse the run's budget, pacing and byte count after the crawl.
+	Client *http.Client
+	// Bytes optionally counts the response body bytes read during the run
+	Bytes *utils.ByteCounter
+	// FetchLimiter optionally caps concurrent extractor fetches across all
+	// publishers. Crawl creates one from the preferences when it is nil.
+	FetchLimiter *utils.FetchLimiter
+	// Cache optionally reuses validation results from previous runs
+	Cache *ValidationCache
+	// Headers optionally maps a publisher (as given in the input) to extra
+	// headers sent with that publisher's same-origin requests. They override
+	// utils.ExtraHeaders key by key.
+	Headers map[string]http.Header
+	// DisplayNames optionally maps a publisher (as given in the input) to a
+	// friendly name copied into its PublisherResult
+	DisplayNames map[string]string
+	// OnResult is called as each publisher finishes, e.g. to drive a progress
+	// bar. Calls are made from a single goroutine, one at a time.
+	OnResult func(result PublisherResult)
+	// DryRun only extracts candidates, skipping validation and selection
+	DryRun bool
+	// ShutdownGrace lets publishers already in flight keep running for this
+	// long after ctx is cancelled (default 0: they are cancelled right away)
+	ShutdownGrace time.Duration
+	// ScoringRules optionally adds custom heuristics to logo selection (see
+	// NewBestLogoSelectorWithRules)
+	ScoringRules []ScoringRule
+	// Logger receives internal diagnostics: candidates found, rejected
+	// candidates (debug), per-publisher outcomes and provider retries
+	// (default slog.Default())
+	Logger *slog.Logger
+}
+
+// withDefaults returns a copy of opts with zero fields set to their defaults
+func (opts Options) withDefaults() Options {
+	if opts.MaxWorkers <= 0 {
+		opts.MaxWorkers = DefaultMaxWorkers
+	}
+	if opts.MaxValidations <= 0 {
+		opts.MaxValidations = DefaultMaxValidations
+	}
+	if opts.ValidationTimeout <= 0 {
+		opts.ValidationTimeout = DefaultValidationTimeout
+	}
+	if opts.PublisherTimeout <= 0 {
+		opts.PublisherTimeout = DefaultPublisherTimeout
+	}
+	if opts.TopN <= 0 {
+		opts.TopN = DefaultTopN
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return opts
 }
 
 // LogoCrawler orchestrates the logo crawling process
@@ -33,82 +167,255 @@ type LogoCrawler struct {
 	selector  *BestLogoSelector
 }
 
-// NewLogoCrawler creates a new logo crawler
-func NewLogoCrawler() *LogoCrawler {
+// NewLogoCrawler creates a new logo crawler using the given HTTP client
+func NewLogoCrawler(client *http.Client, opts Options) *LogoCrawler {
+	opts = opts.withDefaults()
+
+	extractorClient := client
+	if opts.FetchLimiter != nil {
+		extractorClient = opts.FetchLimiter.WrapClient(client)
+	}
+
+	validator := NewLogoValidator(opts.MaxValidations, opts.ValidationTimeout, client, opts.Logger)
+	validator.cache = opts.Cache
+
 	return &LogoCrawler{
-		extractor: NewLogoExtractor(),
-		validator: NewLogoValidator(10), // Max 10 concurrent validations
+		extractor: NewLogoExtractor(extractorClient, opts.Logger),
+		validator: validator,
 		processor: NewDomainProcessor(),
-		selector:  NewBestLogoSelector(),
+		selector:  NewBestLogoSelectorWithRules(opts.ScoringRules...),
 	}
 }
 
-// FetchPublisherLogos returns all valid logos and the best one
-func (lc *LogoCrawler) FetchPublisherLogos(input string, prefs config.Preferences) ([]LogoInfo, *LogoInfo) {
+// FetchPublisherLogos returns all valid logos and the best one. It returns
+// ctx's error when ctx is done before the publisher finished processing.
+func (lc *LogoCrawler) FetchPublisherLogos(ctx context.Context, input string, prefs config.Preferences) ([]LogoInfo, *LogoInfo, error) {
+	var result PublisherResult
+	err := lc.fetchPublisher(ctx, input, prefs, &result)
+	return result.Logos, result.Best, err
+}
+
+// fetchPublisher is FetchPublisherLogos, filling result's Logos, Best,
+// Rejected, ResolvedDomain and step durations. Rejections are kept even when
+// ctx is done.
+func (lc *LogoCrawler) fetchPublisher(ctx context.Context, input string, prefs config.Preferences, result *PublisherResult) error {
 	domain := lc.processor.DetectDomain(input)
 
 	// Step 1: Extract candidates
-	candidates := lc.extractor.ExtractCandidates(domain)
+	start := time.Now()
+	candidates, resolvedDomain := lc.extractor.ExtractCandidates(ctx, domain, prefs)
+	result.ResolvedDomain = resolvedDomain
+	result.ExtractDuration = time.Since(start)
 
 	// Step 2: Validate candidates concurrently
-	valid := lc.validator.ValidateConcurrently(candidates)
+	start = time.Now()
+	valid, rejected := lc.validator.ValidateConcurrently(ctx, candidates, prefs)
+	result.Rejected = rejected
+	result.ValidateDuration = time.Since(start)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Step 3: Select best logo
-	best := lc.selector.SelectBest(valid, prefs)
+	result.Best = lc.selector.SelectBest(valid, prefs, lc.processor.BrandToken(domain))
 
 	// Step 4: Sort logos with best logo first
-	sortedLogos := lc.sortLogosWithBestFirst(valid, best)
+	result.Logos = lc.sortLogosWithBestFirst(valid, result.Best)
 
-	return sortedLogos, best
+	return nil
+}
+
+// processPublisher runs extraction, or extraction, validation and selection,
+// for one publisher. A panic along the way is recovered and returned as an
+// error so that it fails only this publisher rather than the whole run.
+func (lc *LogoCrawler) processPublisher(ctx context.Context, input string, prefs config.Preferences, opts Options, result *PublisherResult) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			opts.Logger.Error("publisher panicked", "publisher", input, "panic", r, "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic occurred: %v", r)
+		}
+	}()
+
+	if opts.DryRun {
+		return lc.extractPublisher(ctx, input, prefs, result)
+	}
+	err = lc.fetchPublisher(ctx, input, prefs, result)
+	brand := lc.processor.BrandToken(lc.processor.DetectDomain(input))
+	result.TopN = lc.selector.SelectTopN(result.Logos, prefs, brand, opts.TopN)
+	return err
+}
+
+// ExtractCandidates returns the candidate logo URLs for a publisher without
+// validating them
+func (lc *LogoCrawler) ExtractCandidates(ctx context.Context, input string, prefs config.Preferences) ([]Candidate, error) {
+	var result PublisherResult
+	err := lc.extractPublisher(ctx, input, prefs, &result)
+	return result.Candidates, err
+}
+
+// extractPublisher is ExtractCandidates, filling result's Candidates,
+// ResolvedDomain and ExtractDuration
+func (lc *LogoCrawler) extractPublisher(ctx context.Context, input string, prefs config.Preferences, result *PublisherResult) error {
+	domain := lc.processor.DetectDomain(input)
+	start := time.Now()
+	candidates, resolvedDomain := lc.extractor.ExtractCandidates(ctx, domain, prefs)
+	result.ExtractDuration = time.Since(start)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	result.Candidates = candidates
+	result.ResolvedDomain = resolvedDomain
+	return nil
 }
 
 // FetchPublisherLogos is the public interface for backward compatibility
 func FetchPublisherLogos(input string, prefs config.Preferences) ([]LogoInfo, *LogoInfo) {
-	crawler := NewLogoCrawler()
-	return crawler.FetchPublisherLogos(input, prefs)
+	opts := Options{}.withDefaults()
+	ctx, cancel := context.WithTimeout(context.Background(), opts.PublisherTimeout)
+	defer cancel()
+
+	crawler := NewLogoCrawler(utils.Client, opts)
+	logos, best, _ := crawler.FetchPublisherLogos(ctx, input, prefs)
+	return logos, best
 }
 
 // FetchPublishersConcurrently processes multiple publishers concurrently
-func FetchPublishersConcurrently(publishers []string, prefs config.Preferences, maxWorkers int) []PublisherResult {
+func FetchPublishersConcurrently(ctx context.Context, publishers []string, prefs config.Preferences, opts Options) []PublisherResult {
+	results, _ := Crawl(ctx, publishers, prefs, opts)
+	return results
+}
+
+// Crawl processes publishers concurrently and returns one result per
+// publisher in input order. It never prints or exits, which makes it
+// suitable for embedding. When ctx is cancelled no further publishers are
+// started and those in flight get opts.ShutdownGrace to finish; those not
+// processed carry ctx's error and Crawl returns it alongside the partial
+// results.
+func Crawl(ctx context.Context, publishers []string, prefs config.Preferences, opts Options) ([]PublisherResult, error) {
+	results := make([]PublisherResult, 0, len(publishers))
+	for result := range Stream(ctx, publishers, prefs, opts) {
+		results = append(results, result)
+	}
+
+	// Sort results by original index to preserve input order
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Index < results[j].Index
+	})
+
+	return results, ctx.Err()
+}
+
+// Stream is Crawl without collecting: it sends each result as soon as its
+// publisher finishes, in completion order, so memory stays flat however many
+// publishers there are. Publishers never started because ctx was cancelled
+// come last, carrying ctx's error. The channel is closed once every
+// publisher has a result; callers must drain it.
+func Stream(ctx context.Context, publishers []string, prefs config.Preferences, opts Options) <-chan PublisherResult {
+	out := make(chan PublisherResult)
 	if len(publishers) == 0 {
-		return nil
+		close(out)
+		return out
+	}
+
+	opts = opts.withDefaults()
+	client := opts.Client
+	if client == nil {
+		client = NewRunClient(prefs, opts)
+	}
+	if opts.FetchLimiter == nil && prefs.Extraction.MaxConcurrentFetches > 0 {
+		opts.FetchLimiter = utils.NewFetchLimiter(prefs.Extraction.MaxConcurrentFetches)
 	}
 
+	// In-flight work outlives ctx by the shutdown grace period
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	stopGrace := context.AfterFunc(ctx, func() {
+		time.AfterFunc(opts.ShutdownGrace, cancelWork)
+	})
+
 	// Create channels for work distribution
 	type publisherTask struct {
 		publisher string
 		index     int
 	}
 
-	publisherChan := make(chan publisherTask, len(publishers))
-	resultChan := make(chan PublisherResult, len(publishers))
+	publisherChan := make(chan publisherTask, opts.MaxWorkers)
+	resultChan := make(chan PublisherResult, opts.MaxWorkers)
 
 	// Start worker goroutines
 	var wg sync.WaitGroup
-	for i := 0; i < maxWorkers; i++ {
+	for i := 0; i < opts.MaxWorkers; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for task := range publisherChan {
+				if ctx.Err() != nil {
+					resultChan <- PublisherResult{
+						Publisher:   task.publisher,
+						DisplayName: opts.DisplayNames[task.publisher],
+						Error:       ctx.Err(),
+						Index:       task.index,
+					}
+					continue
+				}
+
+				if opts.Budget != nil && opts.Budget.Exhausted() {
+					resultChan <- PublisherResult{
+						Publisher:   task.publisher,
+						DisplayName: opts.DisplayNames[task.publisher],
+						Error:       utils.ErrRequestBudgetExhausted,
+						Index:       task.index,
+						Skipped:     true,
+					}
+					continue
+				}
+
+				// Junk input would only waste a full fetch and validate cycle
+				domain, err := NewDomainProcessor().DetectDomainStrict(task.publisher)
+				if err != nil {
+					resultChan <- PublisherResult{
+						Publisher:   task.publisher,
+						DisplayName: opts.DisplayNames[task.publisher],
+						Error:       err,
+						Index:       task.index,
+					}
+					continue
+				}
+
+				publisherClient := client
+				if headers := utils.MergeHeaders(utils.ExtraHeaders, opts.Headers[task.publisher]); len(headers) > 0 {
+					publisherClient = utils.WithOriginHeaders(client, domain, headers)
+				}
+
+				result := PublisherResult{
+					Publisher:   task.publisher,
+					DisplayName: opts.DisplayNames[task.publisher],
+					Index:       task.index,
+				}
+				logoCrawler := NewLogoCrawler(publisherClient, opts)
+				publisherCtx, cancel := context.WithTimeout(workCtx, opts.PublisherTimeout)
 				start := time.Now()
-				logos, best := FetchPublisherLogos(task.publisher, prefs)
+				err = logoCrawler.processPublisher(publisherCtx, task.publisher, prefs, opts, &result)
 				duration := time.Since(start)
+				cancel()
 
-				result := PublisherResult{
-					Publisher: task.publisher,
-					Logos:     logos,
-					Best:      best,
-					Duration:  duration,
-					Index:     task.index,
+				// Distinguish the publisher's own deadline from run cancellation
+				if errors.Is(err, context.DeadlineExceeded) && workCtx.Err() == nil {
+					err = fmt.Errorf("timed out after %v: %w", opts.PublisherTimeout, err)
 				}
 
-				// Handle any panics gracefully
-				defer func() {
-					if r := recover(); r != nil {
-						result.Error = fmt.Errorf("panic occurred: %v", r)
-						resultChan <- result
-					}
-				}()
+				if err != nil {
+					opts.Logger.Warn("publisher failed", "publisher", task.publisher, "duration", duration, "error", err)
+				} else if opts.DryRun {
+					opts.Logger.Info("publisher extracted", "publisher", task.publisher, "duration", duration,
+						"candidates", len(result.Candidates))
+				} else {
+					opts.Logger.Info("publisher processed", "publisher", task.publisher, "duration", duration,
+						"extract", result.ExtractDuration, "validate", result.ValidateDuration,
+						"logos", len(result.Logos), "found_best", result.Best != nil)
+				}
+
+				result.Error = err
+				result.Duration = duration
 
 				resultChan <- result
 			}
@@ -119,10 +426,15 @@ func FetchPublishersConcurrently(publishers []string, prefs config.Preferences,
 	go func() {
 		defer close(publisherChan)
 		for index, publisher := range publishers {
-			publisherChan <- publisherTask{
+			task := publisherTask{
 				publisher: publisher,
 				index:     index,
 			}
+			select {
+			case publisherChan <- task:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
 
@@ -132,18 +444,77 @@ func FetchPublishersConcurrently(publishers []string, prefs config.Preferences,
 		close(resultChan)
 	}()
 
-	// Collect results
-	var results []PublisherResult
-	for result := range resultChan {
-		results = append(results, result)
-	}
+	// Forward results as they complete
+	go func() {
+		defer close(out)
+		defer cancelWork()
+		defer stopGrace()
 
-	// Sort results by original index to preserve input order
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Index < results[j].Index
+		emit := func(result PublisherResult) {
+			if opts.OnResult != nil {
+				opts.OnResult(result)
+			}
+			out <- result
+		}
+
+		processed := make([]bool, len(publishers))
+		for result := range resultChan {
+			processed[result.Index] = true
+			emit(result)
+		}
+
+		// Publishers never dispatched because of cancellation
+		for index, done := range processed {
+			if !done {
+				emit(PublisherResult{
+					Publisher:   publishers[index],
+					DisplayName: opts.DisplayNames[publishers[index]],
+					Error:       ctx.Err(),
+					Index:       index,
+				})
+			}
+		}
+	}()
+
+	return out
+}
+
+// NewRunClient builds the HTTP client shared by all publishers of a run,
+// layering the byte counter, request budget, per-host pacing and provider guard
+func NewRunClient(prefs config.Preferences, opts Options) *http.Client {
+	opts = opts.withDefaults()
+	client := utils.Client
+	if opts.RequestTimeout > 0 {
+		client = utils.NewClient(opts.RequestTimeout)
+	}
+	if opts.Bytes != nil {
+		client = opts.Bytes.WrapClient(client) // Innermost, so provider retries count too
+	}
+	if opts.Budget != nil {
+		client = opts.Budget.WrapClient(client)
+	}
+	if prefs.Throttle.Adaptive {
+		limiter := utils.NewHostLimiter(utils.HostLimiterConfig{
+			LatencyThreshold: prefs.Throttle.LatencyThreshold,
+			InitialDelay:     prefs.Throttle.InitialDelay,
+			MaxDelay:         prefs.Throttle.MaxDelay,
+		})
+		client = limiter.WrapClient(client)
+	}
+	if prefs.Throttle.RequestsPerSecond > 0 {
+		client = utils.NewHostRateLimiter(prefs.Throttle.RequestsPerSecond).WrapClient(client)
+	}
+	guard := utils.NewProviderGuard(utils.ProviderGuardConfig{
+		Hosts:        []string{clearbitHost, googleFaviconHost},
+		Retries:      prefs.Providers.Retries,
+		Backoff:      prefs.Providers.Backoff,
+		MaxBackoff:   prefs.Providers.MaxBackoff,
+		DisableAfter: prefs.Providers.DisableAfter,
+		Logger:       opts.Logger,
 	})
+	client = guard.WrapClient(client)
 
-	return results
+	return client
 }
 
 // sortLogosWithBestFirst sorts logos with the best logo at the beginning
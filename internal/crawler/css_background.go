@@ -0,0 +1,155 @@
+package crawler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
+)
+
+// cssBackgroundRe captures the URL of a background or background-image
+// declaration, quoted or not
+var cssBackgroundRe = regexp.MustCompile(`(?i)background(?:-image)?\s*:[^;}]*?url\(\s*['"]?([^'")]+?)['"]?\s*\)`)
+
+// cssRuleRe matches innermost "selector { declarations }" rules, so rules
+// nested in @media blocks are found too
+var cssRuleRe = regexp.MustCompile(`([^{}]+)\{([^{}]*)\}`)
+
+// cssCommentRe matches CSS comments, stripped before rules are parsed
+var cssCommentRe = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+// maxStylesheetBytes caps how much of a linked stylesheet is read
+const maxStylesheetBytes = 1 << 20
+
+// extractCSSBackgrounds returns background images set on logo elements via
+// inline style attributes, <style> blocks and up to maxStylesheets same-site
+// linked stylesheets. Only elements or selectors whose class or id carries a
+// logo keyword are considered.
+func (le *LogoExtractor) extractCSSBackgrounds(ctx context.Context, doc *goquery.Document, base *url.URL, maxStylesheets int) []Candidate {
+	var candidates []Candidate
+	domain := base.Hostname()
+	add := func(styleBase *url.URL, combined, css string) {
+		for _, match := range cssBackgroundRe.FindAllStringSubmatch(css, -1) {
+			src := strings.TrimSpace(match[1])
+			if src == "" {
+				continue
+			}
+			// The path checks would match random base64 text in data: URIs
+			path := src
+			if utils.IsDataURI(src) {
+				path = ""
+			}
+			if le.isUnrelatedLogo(combined, path, domain) || !le.isDomainLogo(combined, "", domain) {
+				continue
+			}
+			candidates = append(candidates, Candidate{
+				URL:      le.resolveURL(styleBase, src),
+				Source:   SourceCSS,
+				Position: len(candidates),
+			})
+		}
+	}
+
+	// Inline style attributes, matched on the element's class and id
+	doc.Find("[style]").Each(func(i int, sel *goquery.Selection) {
+		style, _ := sel.Attr("style")
+		class, _ := sel.Attr("class")
+		id, _ := sel.Attr("id")
+		add(base, strings.ToLower(class+" "+id), style)
+	})
+
+	// <style> blocks, matched on the rule's selector
+	doc.Find("style").Each(func(i int, sel *goquery.Selection) {
+		le.eachCSSRule(sel.Text(), func(selector, declarations string) {
+			add(base, selector, declarations)
+		})
+	})
+
+	// Linked stylesheets resolve their URLs against the stylesheet itself
+	for _, sheetURL := range le.stylesheetURLs(doc, base, maxStylesheets) {
+		css, ok := le.fetchStylesheet(ctx, sheetURL)
+		if !ok {
+			continue
+		}
+		le.eachCSSRule(css, func(selector, declarations string) {
+			add(sheetURL, selector, declarations)
+		})
+	}
+
+	return candidates
+}
+
+// eachCSSRule calls fn with the lowercased selector and the declarations of
+// every rule in css that sets a background
+func (le *LogoExtractor) eachCSSRule(css string, fn func(selector, declarations string)) {
+	css = cssCommentRe.ReplaceAllString(css, "")
+	for _, rule := range cssRuleRe.FindAllStringSubmatch(css, -1) {
+		if !strings.Contains(strings.ToLower(rule[2]), "background") {
+			continue
+		}
+		fn(strings.ToLower(strings.TrimSpace(rule[1])), rule[2])
+	}
+}
+
+// stylesheetURLs returns up to limit stylesheets linked from the page that
+// are served by the page's own site
+func (le *LogoExtractor) stylesheetURLs(doc *goquery.Document, base *url.URL, limit int) []*url.URL {
+	if limit <= 0 {
+		return nil
+	}
+
+	siteHost := bareHost(base.Hostname())
+	var urls []*url.URL
+	doc.Find("link[rel~='stylesheet']").EachWithBreak(func(i int, sel *goquery.Selection) bool {
+		href, _ := sel.Attr("href")
+		u, err := base.Parse(strings.TrimSpace(href))
+		if href == "" || err != nil || bareHost(u.Hostname()) != siteHost {
+			return true
+		}
+		urls = append(urls, u)
+		return len(urls) < limit
+	})
+	return urls
+}
+
+// fetchStylesheet fetches a stylesheet, honoring robots.txt
+func (le *LogoExtractor) fetchStylesheet(ctx context.Context, sheetURL *url.URL) (string, bool) {
+	if sheetURL.Scheme != "http" && sheetURL.Scheme != "https" {
+		return "", false
+	}
+	if !le.robots.Allowed(ctx, sheetURL.Scheme, sheetURL.Host, sheetURL.EscapedPath()) {
+		return "", false
+	}
+
+	req, err := utils.NewRequestWithContext(ctx, http.MethodGet, sheetURL.String())
+	if err != nil {
+		return "", false
+	}
+
+	resp, err := le.client.Do(req)
+	if err != nil {
+		le.logger.Debug("stylesheet fetch failed", "url", sheetURL.String(), "error", err)
+		return "", false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", false
+	}
+
+	body, err := utils.DecodeBody(resp)
+	if err != nil {
+		return "", false
+	}
+
+	css, err := io.ReadAll(io.LimitReader(body, maxStylesheetBytes))
+	if err != nil {
+		return "", false
+	}
+	return string(css), true
+}
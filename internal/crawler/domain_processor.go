@@ -1,10 +1,17 @@
 package crawler
 
 import (
-	"regexp"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/Tanmay-Thanvi/logo-crawler/config"
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
 )
 
 // DomainProcessor handles domain detection and normalization
@@ -15,131 +22,345 @@ func NewDomainProcessor() *DomainProcessor {
 	return &DomainProcessor{}
 }
 
-// DetectDomain normalizes input into a domain string
+// DetectDomain normalizes input into an ASCII (punycode) domain string
 func (dp *DomainProcessor) DetectDomain(input string) string {
-	domainRe := regexp.MustCompile(`[\w\.-]+\.[a-z]{2,}`)
-	if domainRe.MatchString(input) {
-		return domainRe.FindString(input)
+	return utils.NormalizeDomain(input)
+}
+
+// ErrInvalidPublisher is returned by DetectDomainStrict for input that cannot
+// be a domain or company name
+var ErrInvalidPublisher = errors.New("invalid publisher")
+
+// DetectDomainStrict is DetectDomain, but returns an error wrapping
+// ErrInvalidPublisher when input cannot plausibly be a domain or company
+// name: empty input, input without letters or digits, or a result that is
+// not a valid domain or whose TLD is not on the public suffix list, such as
+// the typo example.comm.
+func (dp *DomainProcessor) DetectDomainStrict(input string) (string, error) {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return "", fmt.Errorf("%w: empty input", ErrInvalidPublisher)
+	}
+	if !strings.ContainsFunc(trimmed, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+		return "", fmt.Errorf("%w %q: no letters or digits", ErrInvalidPublisher, input)
+	}
+
+	domain := dp.DetectDomain(trimmed)
+	if _, err := idna.Registration.ToASCII(domain); err != nil {
+		return "", fmt.Errorf("%w %q: %v", ErrInvalidPublisher, input, err)
 	}
-	// Fallback: assume it's a name -> append .com
-	return strings.ToLower(strings.ReplaceAll(input, " ", "")) + ".com"
+	// Unlisted TLDs come back as their last label, outside the ICANN section;
+	// private suffixes such as github.io are listed and contain a dot
+	if suffix, icann := publicsuffix.PublicSuffix(domain); !icann && !strings.Contains(suffix, ".") {
+		return "", fmt.Errorf("%w %q: unknown TLD %q", ErrInvalidPublisher, input, suffix)
+	}
+	return domain, nil
+}
+
+// minBrandTokenLength keeps short brand tokens such as "go" from matching
+// unrelated image paths
+const minBrandTokenLength = 3
+
+// BrandToken returns the brand part of domain: the label before its public
+// suffix, e.g. "bbc" for www.bbc.co.uk. It returns "" when the domain has no
+// such label or it is too short to be a meaningful match.
+func (dp *DomainProcessor) BrandToken(domain string) string {
+	site, err := publicsuffix.EffectiveTLDPlusOne(strings.ToLower(strings.TrimSpace(domain)))
+	if err != nil {
+		return ""
+	}
+	token, _, _ := strings.Cut(site, ".")
+	if len(token) < minBrandTokenLength || strings.HasPrefix(token, "xn--") {
+		return "" // Punycode labels never appear as such in image paths
+	}
+	return token
 }
 
 // BestLogoSelector selects the best logo based on preferences
-type BestLogoSelector struct{}
+type BestLogoSelector struct {
+	rules []ScoringRule
+}
+
+// ScoringRule is a custom heuristic whose result is added to a logo's
+// built-in score: positive to favor the logo, negative to penalize it.
+// Rules must be safe for concurrent use.
+type ScoringRule func(logo LogoInfo, prefs config.Preferences) int
 
 // NewBestLogoSelector creates a new best logo selector
 func NewBestLogoSelector() *BestLogoSelector {
 	return &BestLogoSelector{}
 }
 
-// SelectBest selects the best logo using intelligent scoring
-func (bls *BestLogoSelector) SelectBest(logos []LogoInfo, prefs config.Preferences) *LogoInfo {
+// NewBestLogoSelectorWithRules creates a best logo selector that adds the
+// given rules to the built-in scoring
+func NewBestLogoSelectorWithRules(rules ...ScoringRule) *BestLogoSelector {
+	return &BestLogoSelector{rules: rules}
+}
+
+// SelectBest selects the best logo using intelligent scoring. brand is the
+// publisher's brand token (see DomainProcessor.BrandToken), "" if unknown.
+// The computed score and its breakdown are stored on each logo's Score and
+// Breakdown fields.
+func (bls *BestLogoSelector) SelectBest(logos []LogoInfo, prefs config.Preferences, brand string) *LogoInfo {
 	if len(logos) == 0 {
 		return nil
 	}
 
-	var best *LogoInfo
+	// Unknown policies and rules are rejected when the app starts; fall back
+	// to whatever weights could be resolved here
+	weights, _ := WeightsForPreferences(prefs)
+
+	bestIndex := -1
 	bestScore := -1
 
-	for _, logo := range logos {
-		score := bls.calculateLogoScore(logo, prefs)
-		if score > bestScore {
+	bls.scoreLogos(logos, prefs, weights, brand)
+
+	for i, logo := range logos {
+		score := logo.Score
+		if score > bestScore || (score == bestScore && bestIndex >= 0 && bls.winsTie(logo, logos[bestIndex], weights)) {
 			bestScore = score
-			best = &logo
+			bestIndex = i
 		}
 	}
 
-	return best
+	if bestIndex < 0 {
+		return nil
+	}
+	// Return a copy so the result does not alias the caller's slice or a
+	// loop variable
+	best := logos[bestIndex]
+	return &best
+}
+
+// SelectTopN returns up to n logos sorted by descending score, ties broken
+// as in SelectBest. Scores and breakdowns are stored as in SelectBest.
+func (bls *BestLogoSelector) SelectTopN(logos []LogoInfo, prefs config.Preferences, brand string, n int) []LogoInfo {
+	if len(logos) == 0 || n <= 0 {
+		return nil
+	}
+
+	weights, _ := WeightsForPreferences(prefs)
+	bls.scoreLogos(logos, prefs, weights, brand)
+
+	ranked := make([]LogoInfo, len(logos))
+	copy(ranked, logos)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return bls.winsTie(ranked[i], ranked[j], weights)
+	})
+
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// scoreLogos sets the Score and Breakdown fields of logos
+func (bls *BestLogoSelector) scoreLogos(logos []LogoInfo, prefs config.Preferences, weights ScoringWeights, brand string) {
+	for i := range logos {
+		var breakdown []RuleScore
+		logos[i].Score = bls.calculateLogoScore(logos[i], prefs, weights, brand, &breakdown)
+		logos[i].Breakdown = breakdown
+	}
+}
+
+// winsTie reports whether logo should replace current when both score equally
+func (bls *BestLogoSelector) winsTie(logo, current LogoInfo, weights ScoringWeights) bool {
+	if weights.TieBreak == TieBreakArea {
+		return logo.Width*logo.Height > current.Width*current.Height
+	}
+	return false
+}
+
+// RuleScore is one scoring rule's contribution to a logo's score
+type RuleScore struct {
+	Rule   string // Rule name, as accepted in the config's scoring section
+	Points int
+}
+
+// ScoreBreakdown returns the rules that changed logo's score, in the order
+// they are applied; their points add up to the score SelectBest assigns.
+// brand is as for SelectBest.
+func (bls *BestLogoSelector) ScoreBreakdown(logo LogoInfo, prefs config.Preferences, brand string) []RuleScore {
+	weights, _ := WeightsForPreferences(prefs)
+	var breakdown []RuleScore
+	bls.calculateLogoScore(logo, prefs, weights, brand, &breakdown)
+	return breakdown
 }
 
-// calculateLogoScore calculates an intelligent score for logo selection
-func (bls *BestLogoSelector) calculateLogoScore(logo LogoInfo, prefs config.Preferences) int {
+// calculateLogoScore calculates an intelligent score for logo selection.
+// When breakdown is not nil, every rule that changed the score is appended
+// to it.
+func (bls *BestLogoSelector) calculateLogoScore(logo LogoInfo, prefs config.Preferences, weights ScoringWeights, brand string, breakdown *[]RuleScore) int {
 	score := 0
+	add := func(rule string, points int) {
+		score += points
+		if breakdown != nil && points != 0 {
+			*breakdown = append(*breakdown, RuleScore{Rule: rule, Points: points})
+		}
+	}
 	url := strings.ToLower(logo.URL)
+	if utils.IsDataURI(url) {
+		url = "" // Keyword rules would match random base64 text
+	}
 
 	// Base score for meeting minimum requirements
 	if logo.Width >= prefs.Preferred.MinWidth && logo.Height >= prefs.Preferred.MinHeight {
-		score += 10
+		add("meets_minimum", weights.MeetsMinimum)
 	} else {
 		// Penalty for not meeting minimum requirements
-		score -= 20
+		add("below_minimum", weights.BelowMinimum)
 	}
 
 	// Bonus for Clearbit logos (usually high quality)
 	if strings.Contains(url, "logo.clearbit.com") {
-		score += 15
+		add("clearbit", weights.Clearbit)
 	}
 
 	// Bonus for favicon.ico (official icon)
 	if strings.Contains(url, "favicon.ico") {
-		score += 12
+		add("favicon", weights.Favicon)
 	}
 
 	// Bonus for apple-touch-icon (high quality)
 	if strings.Contains(url, "apple-touch-icon") {
-		score += 10
+		add("apple_touch_icon", weights.AppleTouchIcon)
+	}
+
+	// Bonus for the brand name in the image path or alt text, e.g.
+	// /img/acme-logo.svg for acme.com: a real brand logo rather than a
+	// generic icon
+	if bls.hasBrandName(logo, brand) {
+		add("brand_name", weights.BrandName)
+	}
+
+	// Bonus for img tags whose alt text calls them a logo
+	if strings.Contains(strings.ToLower(logo.Alt), "logo") {
+		add("alt_logo", weights.AltLogo)
+	}
+
+	// Bonus for a background matching the preferred one; transparent logos
+	// may vanish on it
+	if prefer := prefs.PreferBackground; prefer == config.BackgroundLight || prefer == config.BackgroundDark {
+		switch logo.Background {
+		case prefer:
+			add("background_match", weights.BackgroundMatch)
+		case BackgroundTransparent:
+			add("transparent", weights.Transparent)
+		}
+	}
+
+	// Bonus for icons the site declares itself
+	if logo.Source == SourceLink {
+		add("link_icon", weights.LinkIcon)
+	}
+	if logo.Source == SourceManifest {
+		add("manifest_icon", weights.ManifestIcon)
+	}
+
+	// Bonus for icons declared as scalable (sizes="any")
+	if logo.Scalable {
+		add("scalable_icon", weights.ScalableIcon)
 	}
 
 	// Bonus for SVG logos (scalable)
 	if strings.Contains(url, ".svg") {
-		score += 8
+		add("svg", weights.SVG)
 	}
 
 	// Penalty for dashboard/cover images (usually large)
 	if bls.isDashboardImage(logo, url) {
-		score -= 30
+		add("dashboard", weights.Dashboard)
 	}
 
 	// Penalty for social media images (og:image, twitter:image)
 	if bls.isSocialMediaImage(url) {
-		score -= 25
+		add("social_media", weights.SocialMedia)
 	}
 
 	// Penalty for partner/third-party logos
 	if bls.isPartnerLogo(url) {
-		score -= 40
+		add("partner", weights.Partner)
 	}
 
 	// Penalty for advertisement/promotional content
 	if bls.isAdvertisement(url) {
-		score -= 35
+		add("advertisement", weights.Advertisement)
 	}
 
 	// Bonus for square logos (better for branding)
 	if logo.Width == logo.Height {
-		score += 5
+		add("square", weights.Square)
 	}
 
 	// Bonus for reasonable aspect ratio (not too wide/tall)
 	aspectRatio := float64(logo.Width) / float64(logo.Height)
 	if aspectRatio >= 0.5 && aspectRatio <= 2.0 {
-		score += 3
+		add("reasonable_aspect", weights.ReasonableAspect)
 	}
 
 	// Size-based scoring (prefer medium-sized logos)
 	area := logo.Width * logo.Height
 	if area >= 10000 && area <= 100000 { // 100x100 to 316x316 pixels
-		score += 8
+		add("medium_size", weights.MediumSize)
 	} else if area >= 1000 && area < 10000 { // 32x32 to 100x100 pixels
-		score += 5
+		add("small_size", weights.SmallSize)
 	} else if area > 100000 { // Very large images
-		score -= 10
+		add("large_size", weights.LargeSize)
 	}
 
 	// Bonus for PNG format (good quality)
 	if strings.Contains(url, ".png") {
-		score += 3
+		add("png", weights.PNG)
+	}
+
+	// Bonus for ICO format (universally supported)
+	if strings.Contains(url, ".ico") {
+		add("ico", weights.ICO)
 	}
 
 	// Penalty for very small images
 	if logo.Width < 32 || logo.Height < 32 {
-		score -= 15
+		add("tiny_image", weights.TinyImage)
+	}
+
+	// Mild tiebreak favoring img tags appearing earlier in the page, since
+	// header logos usually come first. Kept low-weight on purpose: DOM order
+	// is a weak signal and should never outweigh the rules above.
+	if logo.Source == SourceImg && logo.Position < weights.EarlyPosition {
+		add("early_position", weights.EarlyPosition-logo.Position)
+	}
+
+	// Custom rules, named by registration order in the breakdown
+	for i, rule := range bls.rules {
+		add(fmt.Sprintf("custom_%d", i+1), rule(logo, prefs))
 	}
 
 	return score
 }
 
+// hasBrandName reports whether brand appears in the logo's alt text or URL
+// path. Clearbit paths are left out since they are always the publisher's
+// domain.
+func (bls *BestLogoSelector) hasBrandName(logo LogoInfo, brand string) bool {
+	if brand == "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(logo.Alt), brand) {
+		return true
+	}
+	if utils.IsDataURI(logo.URL) || isClearbitURL(logo.URL) {
+		return false
+	}
+	u, err := url.Parse(logo.URL)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(u.Path), brand)
+}
+
 // isDashboardImage checks if the logo is likely a dashboard/cover image
 func (bls *BestLogoSelector) isDashboardImage(logo LogoInfo, url string) bool {
 	// Very large images are likely dashboard/cover images
@@ -0,0 +1,170 @@
+package crawler
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/config"
+)
+
+func TestDomainProcessorDetectDomain(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain domain", input: "example.com", want: "example.com"},
+		{name: "uppercase", input: "Example.COM", want: "example.com"},
+		{name: "surrounding space", input: "  example.com\t", want: "example.com"},
+		{name: "www kept", input: "www.example.com", want: "www.example.com"},
+		{name: "uppercase www", input: "WWW.Example.com", want: "www.example.com"},
+		{name: "URL", input: "https://www.example.com/path?q=1", want: "www.example.com"},
+		{name: "unicode label", input: "münchen.de", want: "xn--mnchen-3ya.de"},
+		{name: "uppercase unicode", input: "MÜNCHEN.DE", want: "xn--mnchen-3ya.de"},
+		{name: "unicode URL", input: "https://bücher.example/", want: "xn--bcher-kva.example"},
+		{name: "unicode TLD", input: "пример.рф", want: "xn--e1afmkfd.xn--p1ai"},
+		{name: "CJK", input: "例え.テスト", want: "xn--r8jz45g.xn--zckzah"},
+		{name: "mixed script label", input: "pаypal.com", want: "xn--pypal-4ve.com"}, // Cyrillic а
+		{name: "unicode label with punycode TLD", input: "пример.xn--p1ai", want: "xn--e1afmkfd.xn--p1ai"},
+		{name: "punycode", input: "xn--mnchen-3ya.de", want: "xn--mnchen-3ya.de"},
+		{name: "uppercase punycode", input: "XN--MNCHEN-3YA.DE", want: "xn--mnchen-3ya.de"},
+		{name: "punycode TLD", input: "xn--e1afmkfd.xn--p1ai", want: "xn--e1afmkfd.xn--p1ai"},
+		{name: "company name", input: "Acme Corp", want: "acmecorp.com"},
+		{name: "single word", input: "apple", want: "apple.com"},
+	}
+
+	dp := NewDomainProcessor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dp.DetectDomain(tt.input); got != tt.want {
+				t.Errorf("DetectDomain(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// Logos scored by the built-in rules under the default preferences
+var (
+	mediumLogo  = LogoInfo{URL: "https://example.com/logo.png", Width: 200, Height: 200, Valid: true}
+	tinyIcon    = LogoInfo{URL: "https://example.com/icon.png", Width: 16, Height: 16, Valid: true}
+	heroBanner  = LogoInfo{URL: "https://example.com/hero-banner.jpg", Width: 2000, Height: 1000, Valid: true}
+	partnerLogo = LogoInfo{URL: "https://example.com/partner-badge.gif", Width: 20, Height: 10, Valid: true}
+)
+
+func TestBestLogoSelectorSelectBest(t *testing.T) {
+	tests := []struct {
+		name  string
+		logos []LogoInfo
+		want  string // URL of the best logo, "" for none
+	}{
+		{name: "no logos", want: ""},
+		{name: "single logo", logos: []LogoInfo{mediumLogo}, want: mediumLogo.URL},
+		{name: "best first", logos: []LogoInfo{mediumLogo, tinyIcon, heroBanner}, want: mediumLogo.URL},
+		{name: "best in the middle", logos: []LogoInfo{tinyIcon, mediumLogo, heroBanner}, want: mediumLogo.URL},
+		{name: "best last", logos: []LogoInfo{heroBanner, tinyIcon, mediumLogo}, want: mediumLogo.URL},
+		{name: "every score below -1", logos: []LogoInfo{heroBanner, partnerLogo}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best := NewBestLogoSelector().SelectBest(tt.logos, config.DefaultPreferences(), "")
+			got := ""
+			if best != nil {
+				got = best.URL
+			}
+			if got != tt.want {
+				t.Fatalf("SelectBest() = %q, want %q", got, tt.want)
+			}
+
+			// The result is a copy, not a pointer into logos
+			for i := range tt.logos {
+				if best == &tt.logos[i] {
+					t.Errorf("SelectBest() returned a pointer to logos[%d]", i)
+				}
+			}
+		})
+	}
+}
+
+func TestBestLogoSelectorSelectTopN(t *testing.T) {
+	logos := []LogoInfo{tinyIcon, heroBanner, mediumLogo}
+
+	tests := []struct {
+		name string
+		n    int
+		want []string
+	}{
+		{name: "zero", n: 0, want: nil},
+		{name: "top one", n: 1, want: []string{mediumLogo.URL}},
+		{name: "top two", n: 2, want: []string{mediumLogo.URL, tinyIcon.URL}},
+		{name: "more than available", n: 5, want: []string{mediumLogo.URL, tinyIcon.URL, heroBanner.URL}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]LogoInfo(nil), logos...)
+			top := NewBestLogoSelector().SelectTopN(input, config.DefaultPreferences(), "", tt.n)
+
+			var got []string
+			for _, logo := range top {
+				got = append(got, logo.URL)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SelectTopN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// urlPoints is a scoring rule adding fixed points per logo URL, so tests can
+// rank otherwise identical logos
+func urlPoints(points map[string]int) ScoringRule {
+	return func(logo LogoInfo, _ config.Preferences) int {
+		return points[logo.URL]
+	}
+}
+
+func TestBestLogoSelectorCustomRules(t *testing.T) {
+	a := LogoInfo{URL: "https://example.com/a", Width: 100, Height: 100, Valid: true}
+	b := LogoInfo{URL: "https://example.com/b", Width: 100, Height: 100, Valid: true}
+	c := LogoInfo{URL: "https://example.com/c", Width: 100, Height: 100, Valid: true}
+
+	tests := []struct {
+		name   string
+		points map[string]int
+		want   string // URL of the best logo, "" for none
+	}{
+		{name: "no points keeps the first", want: a.URL},
+		{name: "rule favors the middle", points: map[string]int{b.URL: 10}, want: b.URL},
+		{name: "rule penalizes the first", points: map[string]int{a.URL: -10}, want: b.URL},
+		{name: "rule sinks every score below -1", points: map[string]int{a.URL: -1000, b.URL: -1000, c.URL: -1000}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logos := []LogoInfo{a, b, c}
+			best := NewBestLogoSelectorWithRules(urlPoints(tt.points)).SelectBest(logos, config.DefaultPreferences(), "")
+			got := ""
+			if best != nil {
+				got = best.URL
+			}
+			if got != tt.want {
+				t.Fatalf("SelectBest() = %q, want %q", got, tt.want)
+			}
+
+			// Each logo's breakdown adds up to its score and names the rule
+			for _, logo := range logos {
+				sum := 0
+				for _, rule := range logo.Breakdown {
+					sum += rule.Points
+				}
+				if sum != logo.Score {
+					t.Errorf("%s: breakdown total %d, want score %d", logo.URL, sum, logo.Score)
+				}
+				if points := tt.points[logo.URL]; points != 0 && !slices.Contains(logo.Breakdown, RuleScore{Rule: "custom_1", Points: points}) {
+					t.Errorf("%s: breakdown %v lacks custom_1 %+d", logo.URL, logo.Breakdown, points)
+				}
+			}
+		})
+	}
+}
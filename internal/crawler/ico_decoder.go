@@ -0,0 +1,140 @@
+package crawler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"slices"
+)
+
+// icoMagic starts every ICO file: reserved 0 followed by type 1 (icon)
+const icoMagic = "\x00\x00\x01\x00"
+
+var errInvalidICO = errors.New("ico: invalid file")
+
+func init() {
+	image.RegisterFormat("ico", icoMagic, decodeICO, decodeICOConfig)
+}
+
+// icoEntry is one image embedded in an ICO file
+type icoEntry struct {
+	Width  int
+	Height int
+	Size   uint32
+	Offset uint32
+}
+
+// readICODirectory reads the ICO header and directory of embedded images
+func readICODirectory(r io.Reader) ([]icoEntry, error) {
+	var header [6]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != icoMagic {
+		return nil, errInvalidICO
+	}
+
+	count := int(binary.LittleEndian.Uint16(header[4:]))
+	if count == 0 {
+		return nil, errInvalidICO
+	}
+
+	entries := make([]icoEntry, 0, count)
+	for i := 0; i < count; i++ {
+		var raw [16]byte
+		if _, err := io.ReadFull(r, raw[:]); err != nil {
+			return nil, err
+		}
+
+		// A stored size of 0 means 256 pixels
+		width, height := int(raw[0]), int(raw[1])
+		if width == 0 {
+			width = 256
+		}
+		if height == 0 {
+			height = 256
+		}
+
+		entries = append(entries, icoEntry{
+			Width:  width,
+			Height: height,
+			Size:   binary.LittleEndian.Uint32(raw[8:]),
+			Offset: binary.LittleEndian.Uint32(raw[12:]),
+		})
+	}
+	return entries, nil
+}
+
+// largestICOEntry returns the index of the entry with the largest area
+func largestICOEntry(entries []icoEntry) int {
+	largest := 0
+	for i, entry := range entries {
+		if entry.Width*entry.Height > entries[largest].Width*entries[largest].Height {
+			largest = i
+		}
+	}
+	return largest
+}
+
+// icoSizes returns the distinct sizes of the images embedded in the ICO file
+// starting with head, largest first. It returns nil when head does not hold
+// the whole directory.
+func icoSizes(head []byte) []image.Point {
+	entries, err := readICODirectory(bytes.NewReader(head))
+	if err != nil {
+		return nil
+	}
+
+	var sizes []image.Point
+	for _, entry := range entries {
+		size := image.Pt(entry.Width, entry.Height)
+		if !slices.Contains(sizes, size) {
+			sizes = append(sizes, size)
+		}
+	}
+	slices.SortStableFunc(sizes, func(a, b image.Point) int {
+		return b.X*b.Y - a.X*a.Y
+	})
+	return sizes
+}
+
+// decodeICOConfig reports the dimensions of the largest embedded image
+func decodeICOConfig(r io.Reader) (image.Config, error) {
+	entries, err := readICODirectory(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	largest := entries[largestICOEntry(entries)]
+	return image.Config{
+		ColorModel: color.NRGBAModel,
+		Width:      largest.Width,
+		Height:     largest.Height,
+	}, nil
+}
+
+// decodeICO decodes the largest embedded image. Only PNG-encoded entries,
+// used by all modern high-resolution icons, are supported.
+func decodeICO(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := readICODirectory(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	largest := entries[largestICOEntry(entries)]
+	end := uint64(largest.Offset) + uint64(largest.Size)
+	if end > uint64(len(data)) {
+		return nil, errInvalidICO
+	}
+	return png.Decode(bytes.NewReader(data[largest.Offset:end]))
+}
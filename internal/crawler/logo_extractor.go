@@ -1,76 +1,280 @@
 package crawler
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
 	"net/url"
+	"runtime/debug"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/Tanmay-Thanvi/logo-crawler/config"
 	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
 )
 
+// clearbitHost serves the Clearbit logo API
+const clearbitHost = "logo.clearbit.com"
+
+// googleFaviconHost serves Google's s2 favicon service
+const googleFaviconHost = "www.google.com"
+
+// followLinkPatterns match anchor text or paths of pages likely to show the
+// brand's logo, in order of preference
+var followLinkPatterns = []string{"brand", "about", "press"}
+
+// appleTouchIconSizes are the sized apple-touch-icon variants probed as
+// fallbacks, largest first. Kept short since each one costs a request.
+var appleTouchIconSizes = []int{180, 152, 120}
+
+// Candidate sources, recording where a logo URL was discovered
+const (
+	SourceMeta     = "meta"
+	SourceLink     = "link"
+	SourceImg      = "img"
+	SourceCSS      = "css"
+	SourceManifest = "manifest"
+	SourceFallback = "fallback"
+	SourceClearbit = "clearbit"
+	SourceGoogle   = "google"
+	// SourceData marks inline data: URI images, whatever tag they came from
+	SourceData = "data"
+)
+
+// Candidate is a logo URL discovered during extraction
+type Candidate struct {
+	URL string
+	// Source and Position record where the candidate was found: Position is
+	// its index among candidates of the same source, in document order
+	Source   string
+	Position int
+	// DeclaredWidth and DeclaredHeight hold the size the page declares for the
+	// image (link sizes attribute or srcset width descriptor), 0 when unknown
+	DeclaredWidth  int
+	DeclaredHeight int
+	// Scalable is set for icons declared with sizes="any" (typically SVG)
+	Scalable bool
+	// Alt is the alt text of the img tag the candidate came from
+	Alt string
+}
+
 // LogoExtractor handles logo extraction from various sources
-type LogoExtractor struct{}
+type LogoExtractor struct {
+	client utils.Doer
+	robots *robotsCache
+	logger *slog.Logger
+}
 
-// NewLogoExtractor creates a new logo extractor
-func NewLogoExtractor() *LogoExtractor {
-	return &LogoExtractor{}
+// NewLogoExtractor creates a new logo extractor using the given HTTP client
+// (utils.Client when nil) and logger
+func NewLogoExtractor(client utils.Doer, logger *slog.Logger) *LogoExtractor {
+	if client == nil {
+		client = utils.Client
+	}
+	return &LogoExtractor{
+		client: client,
+		robots: newRobotsCache(client),
+		logger: logger,
+	}
 }
 
-// ExtractCandidates extracts logo candidates from HTML and common paths
-func (le *LogoExtractor) ExtractCandidates(domain string) []string {
+// ExtractCandidates extracts logo candidates from HTML and common paths. It
+// also returns the domain the homepage redirected to, or "" when it stayed on
+// domain; fallbacks and Clearbit then use that domain instead.
+func (le *LogoExtractor) ExtractCandidates(ctx context.Context, domain string, prefs config.Preferences) ([]Candidate, string) {
 	baseURL := "https://" + domain
 
-	var candidates []string
+	var candidates []Candidate
 
 	// Always try web scraping first to get more options
-	htmlCandidates := le.extractFromHTML(baseURL)
+	htmlCandidates, resolvedDomain := le.extractFromHTML(ctx, baseURL, prefs)
+	fallbackDomain := domain
+	if resolvedDomain != "" {
+		le.logger.Debug("homepage redirected to another domain", "domain", domain, "resolved", resolvedDomain)
+		fallbackDomain = resolvedDomain
+	}
+
+	// Brand and press pages from the sitemap, when the homepage had nothing
+	if len(htmlCandidates) == 0 && prefs.Extraction.Sitemap {
+		htmlCandidates = le.extractFromSitemap(ctx, baseURL, prefs)
+	}
 	candidates = append(candidates, htmlCandidates...)
 
 	// Always add common fallbacks
-	candidates = append(candidates, le.getCommonFallbacks(domain)...)
+	candidates = append(candidates, le.getCommonFallbacks(fallbackDomain)...)
 
 	// Add Clearbit as a fallback (but not primary)
-	candidates = append(candidates, le.getClearbitLogo(domain))
+	if prefs.Providers.UseClearbit {
+		candidates = append(candidates, Candidate{URL: le.getClearbitLogo(fallbackDomain, prefs.Providers.ClearbitSize), Source: SourceClearbit})
+	}
 
-	return le.unique(candidates)
+	// Google's favicon service as a second, independent provider
+	if prefs.Providers.UseGoogleFavicons {
+		candidates = append(candidates, Candidate{URL: le.getGoogleFavicon(fallbackDomain), Source: SourceGoogle})
+	}
+
+	// Collapse URLs that differ only by volatile query params
+	for i := range candidates {
+		candidates[i].URL = le.stripQueryParams(candidates[i].URL, prefs.Extraction.StripQueryParams)
+		if utils.IsDataURI(candidates[i].URL) {
+			candidates[i].Source = SourceData
+		}
+	}
+
+	candidates = le.unique(candidates)
+
+	le.logger.Debug("candidates extracted", "domain", domain, "count", len(candidates))
+	for _, candidate := range candidates {
+		le.logger.Debug("candidate found", "domain", domain, "url", candidate.URL, "source", candidate.Source)
+	}
+
+	return candidates, resolvedDomain
 }
 
-// extractFromHTML extracts logo candidates from HTML meta tags and links
-func (le *LogoExtractor) extractFromHTML(baseURL string) []string {
-	var allCandidates []string
+// extractFromHTML extracts logo candidates from HTML meta tags and links. It
+// also returns the host the first fetched page redirected to when that is a
+// different domain (www. aside), or "" otherwise.
+func (le *LogoExtractor) extractFromHTML(ctx context.Context, baseURL string, prefs config.Preferences) ([]Candidate, string) {
+	var allCandidates []Candidate
+	var resolvedDomain string
 
 	// Try multiple URL variations to get more logos
 	urls := []string{baseURL}
 
 	// Add www version if not already present
-	if !strings.HasPrefix(baseURL, "https://www.") {
+	if prefs.Extraction.ProbeWWW && !strings.HasPrefix(baseURL, "https://www.") {
 		wwwURL := strings.Replace(baseURL, "https://", "https://www.", 1)
 		urls = append(urls, wwwURL)
 	}
 
-	// Try each URL variation
-	for _, url := range urls {
-		candidates := le.extractFromSingleURL(url)
+	// Fetch the URL variations concurrently, keeping their results in order
+	type pageResult struct {
+		candidates []Candidate
+		finalURL   *url.URL
+		followURL  string
+	}
+	pages := make([]pageResult, len(urls))
+	var wg sync.WaitGroup
+	for i, pageURL := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Out of reach of the worker's recover; a panic loses only this page
+			defer func() {
+				if r := recover(); r != nil {
+					le.logger.Error("page extraction panicked", "url", pageURL, "panic", r, "stack", string(debug.Stack()))
+				}
+			}()
+			candidates, finalURL, followURL := le.extractFromSingleURL(ctx, pageURL, prefs)
+			pages[i] = pageResult{candidates: candidates, finalURL: finalURL, followURL: followURL}
+		}()
+	}
+	wg.Wait()
+
+	fetched := false
+	followURL := ""
+	for i, page := range pages {
+		allCandidates = append(allCandidates, page.candidates...)
+		if page.finalURL != nil && !fetched {
+			fetched = true
+			if final, requested := bareHost(page.finalURL.Hostname()), bareHost(hostOf(urls[i])); final != requested {
+				resolvedDomain = final
+			}
+		}
+		if followURL == "" {
+			followURL = page.followURL
+		}
+	}
+
+	// One hop to a brand, about or press page when the homepage shows no logo
+	if prefs.Extraction.FollowLinks && followURL != "" && !hasPageLogo(allCandidates) {
+		le.logger.Debug("following link for logo", "url", followURL)
+		candidates, _, _ := le.extractFromSingleURL(ctx, followURL, prefs)
 		allCandidates = append(allCandidates, candidates...)
 	}
 
-	return le.unique(allCandidates)
+	return le.unique(allCandidates), resolvedDomain
 }
 
-// extractFromSingleURL extracts logos from a single URL
-func (le *LogoExtractor) extractFromSingleURL(baseURL string) []string {
-	resp, err := utils.Client.Get(baseURL)
+// hasPageLogo reports whether any candidate is a logo shown on the page (an
+// img tag or CSS background) rather than an icon or sharing image
+func hasPageLogo(candidates []Candidate) bool {
+	for _, candidate := range candidates {
+		if candidate.Source == SourceImg || candidate.Source == SourceCSS {
+			return true
+		}
+	}
+	return false
+}
+
+// hostOf returns the host name of rawURL, or "" when it does not parse
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
 	if err != nil {
-		return nil
+		return ""
 	}
-	defer resp.Body.Close()
+	return u.Hostname()
+}
+
+// bareHost lowercases host and drops a leading "www."
+func bareHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+// extractFromSingleURL extracts logos from a single URL. It also returns the
+// page's final URL after redirects, or nil when the page was not fetched, and
+// the page's first brand, about or press link ("" when it has none).
+func (le *LogoExtractor) extractFromSingleURL(ctx context.Context, baseURL string, prefs config.Preferences) ([]Candidate, *url.URL, string) {
+	// Respect robots.txt; disallowed sites still get fallbacks and Clearbit
+	if u, err := url.Parse(baseURL); err == nil {
+		path := u.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		if !le.robots.Allowed(ctx, u.Scheme, u.Host, path) {
+			le.logger.Debug("page disallowed by robots.txt", "url", baseURL)
+			return nil, nil, ""
+		}
+	}
+
+	resp, err := le.fetchPage(ctx, baseURL, prefs)
 	if err != nil {
-		return nil
+		le.logger.Debug("page fetch failed", "url", baseURL, "error", err)
+		return nil, nil, ""
 	}
 
-	var candidates []string
+	// Error pages (404, 500, ...) would only yield junk candidates
+	if !le.isParsableStatus(resp.StatusCode, prefs.Extraction.AllowedStatusCodes) {
+		resp.Body.Close()
+		le.logger.Debug("page skipped", "url", baseURL, "status", resp.StatusCode)
+		return nil, resp.Request.URL, ""
+	}
+
+	// Close the page before fetching the manifest so a fetch slot is not
+	// held across both requests
+	body, err := utils.DecodeBody(resp)
+	if err != nil {
+		resp.Body.Close()
+		le.logger.Debug("page decode failed", "url", baseURL, "error", err)
+		return nil, resp.Request.URL, ""
+	}
+	doc, err := goquery.NewDocumentFromReader(body)
+	resp.Body.Close()
+	if err != nil {
+		le.logger.Debug("page parse failed", "url", baseURL, "error", err)
+		return nil, resp.Request.URL, ""
+	}
+
+	var candidates []Candidate
 	base := resp.Request.URL
 
 	// Extract from meta tags
@@ -82,22 +286,115 @@ func (le *LogoExtractor) extractFromSingleURL(baseURL string) []string {
 	// Extract from img tags with logo-related attributes
 	candidates = append(candidates, le.extractImgTags(doc, base)...)
 
-	return candidates
+	// Extract CSS background images set on logo elements
+	candidates = append(candidates, le.extractCSSBackgrounds(ctx, doc, base, prefs.Extraction.MaxStylesheets)...)
+
+	// Extract from the web app manifest
+	candidates = append(candidates, le.extractManifestIcons(ctx, doc, base)...)
+
+	followURL := ""
+	if prefs.Extraction.FollowLinks {
+		followURL = le.findFollowLink(doc, base)
+	}
+	return candidates, base, followURL
+}
+
+// fetchPage GETs a page. When AllowHTTP is set and an https:// page fails
+// to connect or speaks no TLS, it is retried once over plain http. Invalid
+// certificates are never retried in cleartext; INSECURE_TLS covers those.
+func (le *LogoExtractor) fetchPage(ctx context.Context, pageURL string, prefs config.Preferences) (*http.Response, error) {
+	req, err := utils.NewRequestWithContext(ctx, http.MethodGet, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := le.client.Do(req)
+	if isCertificateError(err) {
+		le.logger.Error("page certificate verification failed", "url", pageURL, "error", err)
+	}
+	if err == nil || !prefs.Extraction.AllowHTTP || req.URL.Scheme != "https" || !isConnectionError(err) {
+		return resp, err
+	}
+
+	httpURL := *req.URL
+	httpURL.Scheme = "http"
+	path := httpURL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if !le.robots.Allowed(ctx, httpURL.Scheme, httpURL.Host, path) {
+		return nil, err
+	}
+	le.logger.Debug("retrying page over http", "url", pageURL, "error", err)
+
+	httpReq, httpErr := utils.NewRequestWithContext(ctx, http.MethodGet, httpURL.String())
+	if httpErr != nil {
+		return nil, err
+	}
+	return le.client.Do(httpReq)
+}
+
+// isConnectionError reports whether err means the server could not be
+// reached or spoke no TLS: refused or reset connections and TLS record
+// failures. Timeouts, cancellation, DNS and certificate failures are not; a
+// bad certificate may be an attack and must not downgrade to cleartext.
+func isConnectionError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.As(err, &dnsErr) || isCertificateError(err) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return false
+	}
+
+	var opErr *net.OpError
+	var recordErr tls.RecordHeaderError
+	return errors.As(err, &opErr) || errors.As(err, &recordErr) ||
+		errors.Is(err, http.ErrSchemeMismatch) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isCertificateError reports whether err is a failed certificate
+// verification: expired, self-signed or for another host
+func isCertificateError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	return errors.As(err, &certErr)
+}
+
+// isParsableStatus reports whether a page with the given status should be parsed
+func (le *LogoExtractor) isParsableStatus(status int, allowed []int) bool {
+	if status >= 200 && status < 300 {
+		return true
+	}
+	for _, code := range allowed {
+		if status == code {
+			return true
+		}
+	}
+	return false
 }
 
 // extractMetaTags extracts logo URLs from meta tags
-func (le *LogoExtractor) extractMetaTags(doc *goquery.Document, base *url.URL) []string {
-	var candidates []string
+func (le *LogoExtractor) extractMetaTags(doc *goquery.Document, base *url.URL) []Candidate {
+	var candidates []Candidate
 	metaProps := []string{"og:image", "twitter:image", "og:image:url"}
 
 	for _, prop := range metaProps {
 		// Check property attribute
 		if content, exists := doc.Find("meta[property='" + prop + "']").Attr("content"); exists {
-			candidates = append(candidates, le.resolveURL(base, content))
+			candidates = append(candidates, Candidate{
+				URL:      le.resolveURL(base, content),
+				Source:   SourceMeta,
+				Position: len(candidates),
+			})
 		}
 		// Check name attribute
 		if content, exists := doc.Find("meta[name='" + prop + "']").Attr("content"); exists {
-			candidates = append(candidates, le.resolveURL(base, content))
+			candidates = append(candidates, Candidate{
+				URL:      le.resolveURL(base, content),
+				Source:   SourceMeta,
+				Position: len(candidates),
+			})
 		}
 	}
 
@@ -105,24 +402,45 @@ func (le *LogoExtractor) extractMetaTags(doc *goquery.Document, base *url.URL) [
 }
 
 // extractLinkTags extracts logo URLs from link tags
-func (le *LogoExtractor) extractLinkTags(doc *goquery.Document, base *url.URL) []string {
-	var candidates []string
+func (le *LogoExtractor) extractLinkTags(doc *goquery.Document, base *url.URL) []Candidate {
+	var candidates []Candidate
 
 	doc.Find("link[rel]").Each(func(i int, sel *goquery.Selection) {
 		rel, _ := sel.Attr("rel")
 		href, _ := sel.Attr("href")
 		if strings.Contains(strings.ToLower(rel), "icon") && href != "" {
-			candidates = append(candidates, le.resolveURL(base, href))
+			sizes, _ := sel.Attr("sizes")
+			width, height, scalable := le.parseSizes(sizes)
+			candidates = append(candidates, Candidate{
+				URL:            le.resolveURL(base, href),
+				Source:         SourceLink,
+				Position:       len(candidates),
+				DeclaredWidth:  width,
+				DeclaredHeight: height,
+				Scalable:       scalable,
+			})
+		}
+	})
+
+	// Validate scalable and larger declared icons first, so the best
+	// candidates are fetched before the 16x16 variants. Position keeps the
+	// document order.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Scalable != candidates[j].Scalable {
+			return candidates[i].Scalable
 		}
+		return candidates[i].DeclaredWidth*candidates[i].DeclaredHeight >
+			candidates[j].DeclaredWidth*candidates[j].DeclaredHeight
 	})
 
 	return candidates
 }
 
 // extractImgTags extracts logo URLs from img tags with logo-related attributes
-func (le *LogoExtractor) extractImgTags(doc *goquery.Document, base *url.URL) []string {
-	var candidates []string
+func (le *LogoExtractor) extractImgTags(doc *goquery.Document, base *url.URL) []Candidate {
+	var candidates []Candidate
 	domain := base.Hostname()
+	position := 0
 
 	// Look for img tags with logo-related attributes
 	doc.Find("img").Each(func(i int, sel *goquery.Selection) {
@@ -139,20 +457,208 @@ func (le *LogoExtractor) extractImgTags(doc *goquery.Document, base *url.URL) []
 		// Combine all attributes for checking
 		combined := strings.ToLower(alt + " " + class + " " + id)
 
+		// The keyword checks below would match random base64 text
+		path := src
+		if utils.IsDataURI(src) {
+			path = ""
+		}
+
 		// Skip if it's clearly not a domain logo
-		if le.isUnrelatedLogo(combined, src, domain) {
+		if le.isUnrelatedLogo(combined, path, domain) {
 			return
 		}
 
 		// Check if this looks like a domain logo
-		if le.isDomainLogo(combined, src, domain) {
-			candidates = append(candidates, le.resolveURL(base, src))
+		if le.isDomainLogo(combined, path, domain) {
+			alt = strings.TrimSpace(alt)
+			candidates = append(candidates, Candidate{
+				URL:      le.resolveURL(base, src),
+				Source:   SourceImg,
+				Position: position,
+				Alt:      alt,
+			})
+
+			// Include srcset variants, which may declare their width
+			if srcset, ok := sel.Attr("srcset"); ok {
+				for _, variant := range le.parseSrcset(base, srcset, position) {
+					variant.Alt = alt
+					candidates = append(candidates, variant)
+				}
+			}
+			position++
 		}
 	})
 
 	return candidates
 }
 
+// parseSizes parses a link sizes attribute (e.g. "32x32 180x180") and
+// returns the largest declared size, or zeros when none is declared.
+// scalable reports the "any" keyword, used for vector icons.
+func (le *LogoExtractor) parseSizes(sizes string) (width, height int, scalable bool) {
+	for _, size := range strings.Fields(strings.ToLower(sizes)) {
+		if size == "any" {
+			scalable = true
+			continue
+		}
+		parts := strings.SplitN(size, "x", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		w, errW := strconv.Atoi(parts[0])
+		h, errH := strconv.Atoi(parts[1])
+		if errW != nil || errH != nil {
+			continue
+		}
+		if w*h > width*height {
+			width, height = w, h
+		}
+	}
+	return width, height, scalable
+}
+
+// parseSrcset parses an img srcset attribute into candidates, recording the
+// declared width for entries using a width descriptor (e.g. "logo.png 200w").
+// Variants share the position of the img tag they belong to.
+func (le *LogoExtractor) parseSrcset(base *url.URL, srcset string, position int) []Candidate {
+	// Commas inside data: URIs make the list ambiguous
+	if strings.Contains(strings.ToLower(srcset), "data:") {
+		return nil
+	}
+
+	var candidates []Candidate
+	for _, entry := range strings.Split(srcset, ",") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+
+		candidate := Candidate{
+			URL:      le.resolveURL(base, fields[0]),
+			Source:   SourceImg,
+			Position: position,
+		}
+		if len(fields) > 1 && strings.HasSuffix(fields[1], "w") {
+			if width, err := strconv.Atoi(strings.TrimSuffix(fields[1], "w")); err == nil {
+				candidate.DeclaredWidth = width
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// manifestIcon is an entry of a web app manifest's icons array
+type manifestIcon struct {
+	Src     string `json:"src"`
+	Sizes   string `json:"sizes"`
+	Purpose string `json:"purpose"`
+}
+
+// extractManifestIcons fetches the web app manifest referenced by the page
+// and returns its icons, listing "any" and "maskable" purposes first.
+// Unreachable or malformed manifests yield no candidates.
+func (le *LogoExtractor) extractManifestIcons(ctx context.Context, doc *goquery.Document, base *url.URL) []Candidate {
+	href, exists := doc.Find("link[rel='manifest']").Attr("href")
+	if !exists || href == "" {
+		return nil
+	}
+
+	manifestURL, err := base.Parse(href)
+	if err != nil {
+		return nil
+	}
+
+	req, err := utils.NewRequestWithContext(ctx, http.MethodGet, manifestURL.String())
+	if err != nil {
+		return nil
+	}
+
+	resp, err := le.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil
+	}
+
+	body, err := utils.DecodeBody(resp)
+	if err != nil {
+		return nil
+	}
+
+	var manifest struct {
+		Icons []manifestIcon `json:"icons"`
+	}
+	if err := json.NewDecoder(io.LimitReader(body, 1<<20)).Decode(&manifest); err != nil {
+		return nil
+	}
+
+	var preferred, others []Candidate
+	for _, icon := range manifest.Icons {
+		if icon.Src == "" {
+			continue
+		}
+
+		width, height, scalable := le.parseSizes(icon.Sizes)
+		candidate := Candidate{
+			URL:            le.resolveURL(manifestURL, icon.Src),
+			Source:         SourceManifest,
+			DeclaredWidth:  width,
+			DeclaredHeight: height,
+			Scalable:       scalable,
+		}
+
+		// A missing purpose means "any"
+		purpose := strings.ToLower(icon.Purpose)
+		if purpose == "" || strings.Contains(purpose, "any") || strings.Contains(purpose, "maskable") {
+			preferred = append(preferred, candidate)
+		} else {
+			others = append(others, candidate)
+		}
+	}
+
+	candidates := append(preferred, others...)
+	for i := range candidates {
+		candidates[i].Position = i
+	}
+	return candidates
+}
+
+// findFollowLink returns the first same-site link on the page whose text or
+// path matches followLinkPatterns, most relevant pattern first, or ""
+func (le *LogoExtractor) findFollowLink(doc *goquery.Document, base *url.URL) string {
+	type link struct {
+		url  *url.URL
+		text string
+	}
+	var links []link
+	siteHost := bareHost(base.Hostname())
+	doc.Find("a[href]").Each(func(i int, sel *goquery.Selection) {
+		href, _ := sel.Attr("href")
+		u, err := base.Parse(strings.TrimSpace(href))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || bareHost(u.Hostname()) != siteHost {
+			return
+		}
+		u.Fragment = ""
+		if u.Path == base.Path || (u.Path == "" && base.Path == "/") {
+			return // The page itself
+		}
+		links = append(links, link{url: u, text: strings.ToLower(sel.Text())})
+	})
+
+	for _, pattern := range followLinkPatterns {
+		for _, l := range links {
+			if strings.Contains(l.text, pattern) || strings.Contains(strings.ToLower(l.url.Path), pattern) {
+				return l.url.String()
+			}
+		}
+	}
+	return ""
+}
+
 // isDomainLogo checks if the image is likely a domain-specific logo
 func (le *LogoExtractor) isDomainLogo(combined, src, domain string) bool {
 	// Check for domain-specific logo keywords
@@ -251,7 +757,7 @@ func (le *LogoExtractor) isUnrelatedLogo(combined, src, domain string) bool {
 }
 
 // getCommonFallbacks returns common logo/icon paths for a domain
-func (le *LogoExtractor) getCommonFallbacks(domain string) []string {
+func (le *LogoExtractor) getCommonFallbacks(domain string) []Candidate {
 	base := "https://" + domain
 	paths := []string{
 		"/favicon.ico",
@@ -259,25 +765,45 @@ func (le *LogoExtractor) getCommonFallbacks(domain string) []string {
 		"/favicon.svg",
 		"/apple-touch-icon.png",
 		"/apple-touch-icon-precomposed.png",
+	}
+	for _, size := range appleTouchIconSizes {
+		paths = append(paths, fmt.Sprintf("/apple-touch-icon-%dx%d.png", size, size))
+	}
+	paths = append(paths,
 		"/logo.png",
 		"/assets/logo.png",
 		"/images/logo.png",
+	)
+
+	var candidates []Candidate
+	for i, path := range paths {
+		candidates = append(candidates, Candidate{URL: base + path, Source: SourceFallback, Position: i})
 	}
+	return candidates
+}
 
-	var urls []string
-	for _, path := range paths {
-		urls = append(urls, base+path)
+// getClearbitLogo returns the Clearbit logo API URL for the domain, asking
+// for a size pixels wide logo unless size is 0
+func (le *LogoExtractor) getClearbitLogo(domain string, size int) string {
+	logoURL := "https://" + clearbitHost + "/" + domain
+	if size > 0 {
+		logoURL += "?size=" + strconv.Itoa(size)
 	}
-	return urls
+	return logoURL
 }
 
-// getClearbitLogo returns the Clearbit logo API URL for the domain
-func (le *LogoExtractor) getClearbitLogo(domain string) string {
-	return "https://logo.clearbit.com/" + domain
+// getGoogleFavicon returns the Google s2 favicon URL for the domain, asking
+// for its largest 128px size
+func (le *LogoExtractor) getGoogleFavicon(domain string) string {
+	return "https://" + googleFaviconHost + "/s2/favicons?domain=" + url.QueryEscape(domain) + "&sz=128"
 }
 
 // resolveURL resolves a relative URL against a base URL
 func (le *LogoExtractor) resolveURL(base *url.URL, href string) string {
+	// Inline images are used as they are
+	if utils.IsDataURI(strings.TrimSpace(href)) {
+		return strings.TrimSpace(href)
+	}
 	u, err := base.Parse(href)
 	if err != nil {
 		return href
@@ -285,13 +811,53 @@ func (le *LogoExtractor) resolveURL(base *url.URL, href string) string {
 	return u.String()
 }
 
-// unique removes duplicate URLs from the slice
-func (le *LogoExtractor) unique(list []string) []string {
+// stripQueryParams removes the given query parameters (matched case-insensitively)
+// from a URL, leaving meaningful parameters such as favicon sizes untouched.
+// The remaining parameters keep their original order and escaping, since some
+// servers sign or route on the exact query string
+func (le *LogoExtractor) stripQueryParams(rawURL string, params []string) string {
+	if len(params) == 0 || !strings.Contains(rawURL, "?") || utils.IsDataURI(rawURL) {
+		return rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	pairs := strings.Split(u.RawQuery, "&")
+	kept := pairs[:0]
+	for _, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		strip := false
+		for _, param := range params {
+			if strings.EqualFold(key, param) {
+				strip = true
+				break
+			}
+		}
+		if !strip {
+			kept = append(kept, pair)
+		}
+	}
+	if len(kept) == len(pairs) {
+		return rawURL
+	}
+
+	u.RawQuery = strings.Join(kept, "&")
+	return u.String()
+}
+
+// unique removes candidates with duplicate URLs from the slice
+func (le *LogoExtractor) unique(list []Candidate) []Candidate {
 	seen := make(map[string]bool)
-	var out []string
+	var out []Candidate
 	for _, v := range list {
-		if v != "" && !seen[v] {
-			seen[v] = true
+		if v.URL != "" && !seen[v.URL] {
+			seen[v.URL] = true
 			out = append(out, v)
 		}
 	}
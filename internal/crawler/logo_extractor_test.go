@@ -0,0 +1,102 @@
+package crawler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/config"
+)
+
+func TestLogoExtractorExtractCandidates(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, `<html><head>
+			<link rel="icon" href="/favicon-32.png" sizes="32x32">
+			<meta property="og:image" content="/og-image.jpg">
+		</head><body>
+			<img src="/img/acme-logo.svg" alt="Acme logo">
+		</body></html>`)
+	})
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	doer := &countingDoer{doer: srv.Client()}
+	extractor := NewLogoExtractor(doer, discardLogger())
+
+	prefs := config.DefaultPreferences()
+	prefs.Extraction.ProbeWWW = false
+	prefs.Providers.UseClearbit = false
+	domain := strings.TrimPrefix(srv.URL, "https://")
+
+	candidates, resolved := extractor.ExtractCandidates(context.Background(), domain, prefs)
+	if doer.requests.Load() == 0 {
+		t.Fatal("no request went through the injected Doer")
+	}
+	if resolved != "" {
+		t.Errorf("resolved domain = %q, want none", resolved)
+	}
+
+	want := []Candidate{
+		{URL: srv.URL + "/favicon-32.png", Source: SourceLink},
+		{URL: srv.URL + "/og-image.jpg", Source: SourceMeta},
+		{URL: srv.URL + "/img/acme-logo.svg", Source: SourceImg},
+	}
+	for _, w := range want {
+		if !slices.ContainsFunc(candidates, func(c Candidate) bool { return c.URL == w.URL && c.Source == w.Source }) {
+			t.Errorf("missing %s candidate %s in %v", w.Source, w.URL, candidates)
+		}
+	}
+}
+
+func TestLogoExtractorFetchPageKeepsCertificateErrors(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<html></html>")
+	}))
+	defer srv.Close()
+
+	// A client that does not trust the test server's self-signed certificate
+	extractor := NewLogoExtractor(&http.Client{}, discardLogger())
+	prefs := config.DefaultPreferences()
+	prefs.Extraction.AllowHTTP = true
+
+	resp, err := extractor.fetchPage(context.Background(), srv.URL+"/", prefs)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("fetchPage() fell back to %s, want the certificate error", resp.Request.URL)
+	}
+	if !isCertificateError(err) {
+		t.Errorf("fetchPage() error = %v, want a certificate verification error", err)
+	}
+}
+
+func TestLogoExtractorStripQueryParams(t *testing.T) {
+	params := config.DefaultPreferences().Extraction.StripQueryParams
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "no query", input: "https://example.com/logo.png", want: "https://example.com/logo.png"},
+		{name: "nothing to strip", input: "https://example.com/logo.png?z=1&a=%7e&t=2", want: "https://example.com/logo.png?z=1&a=%7e&t=2"},
+		{name: "cache buster", input: "https://example.com/logo.png?v=123", want: "https://example.com/logo.png"},
+		{name: "order and escaping kept", input: "https://example.com/logo.png?z=1&V=2&a=%7e&sig=a+b", want: "https://example.com/logo.png?z=1&a=%7e&sig=a+b"},
+		{name: "escaped key", input: "https://example.com/logo.png?size=64&%76er=9", want: "https://example.com/logo.png?size=64"},
+		{name: "data URI", input: "data:image/png;base64,AAAA?v=1", want: "data:image/png;base64,AAAA?v=1"},
+	}
+
+	extractor := NewLogoExtractor(http.DefaultClient, discardLogger())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractor.stripQueryParams(tt.input, params); got != tt.want {
+				t.Errorf("stripQueryParams(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,45 +1,92 @@
 package crawler
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"image"
+	"image/color"
 	_ "image/gif"
 	_ "image/jpeg"
 	_ "image/png"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	neturl "net/url"
+	"runtime/debug"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/Tanmay-Thanvi/logo-crawler/config"
 	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
+	_ "golang.org/x/image/webp"
 )
 
 // LogoValidator handles concurrent logo validation
 type LogoValidator struct {
 	semaphore chan struct{}
+	timeout   time.Duration
+	client    utils.Doer
+	logger    *slog.Logger
+	cache     *ValidationCache // Optional, reuses results across runs
 }
 
-// NewLogoValidator creates a new logo validator
-func NewLogoValidator(maxConcurrent int) *LogoValidator {
+// NewLogoValidator creates a new logo validator using the given HTTP client
+// (utils.Client when nil) and logger. timeout bounds a whole ValidateConcurrently call.
+func NewLogoValidator(maxConcurrent int, timeout time.Duration, client utils.Doer, logger *slog.Logger) *LogoValidator {
+	if client == nil {
+		client = utils.Client
+	}
 	return &LogoValidator{
 		semaphore: make(chan struct{}, maxConcurrent),
+		timeout:   timeout,
+		client:    client,
+		logger:    logger,
 	}
 }
 
-// ValidateConcurrently validates multiple logo URLs concurrently
-func (lv *LogoValidator) ValidateConcurrently(candidates []string) []LogoInfo {
+// RejectedCandidate records why a candidate did not become a valid logo
+type RejectedCandidate struct {
+	URL    string
+	Source string
+	Reason string // e.g. "http 404", "timeout", "decode failed: ...", "dimensions 0x0"
+}
+
+// validatedLogo is the outcome of validating one candidate: either a logo or
+// a rejection, along with the candidate's extraction order
+type validatedLogo struct {
+	index    int
+	logo     LogoInfo
+	variants []LogoInfo // The smaller images of a multi-image ICO
+	rejected *RejectedCandidate
+}
+
+// ValidateConcurrently validates multiple logo URLs concurrently. Logos are
+// returned in extraction order, and when several URLs serve the same image
+// bytes only the first one is kept. Each smaller image of a multi-image ICO
+// follows its file as a logo of its own (see icoVariants). Candidates that
+// failed validation are returned with the reason, also in extraction order.
+func (lv *LogoValidator) ValidateConcurrently(ctx context.Context, candidates []Candidate, prefs config.Preferences) ([]LogoInfo, []RejectedCandidate) {
 	if len(candidates) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, lv.timeout)
 	defer cancel()
 
-	results := make(chan LogoInfo, len(candidates))
+	results := make(chan validatedLogo, len(candidates))
 	var wg sync.WaitGroup
 
-	for _, url := range candidates {
+	for index, candidate := range candidates {
 		wg.Add(1)
-		go lv.validateSingleLogo(ctx, url, results, &wg)
+		go lv.validateSingleLogo(ctx, index, candidate, prefs, results, &wg)
 	}
 
 	go func() {
@@ -47,52 +94,633 @@ func (lv *LogoValidator) ValidateConcurrently(candidates []string) []LogoInfo {
 		close(results)
 	}()
 
+	slots := make([]*validatedLogo, len(candidates))
+	for result := range results {
+		slots[result.index] = &result
+	}
+
 	var valid []LogoInfo
-	for logo := range results {
+	var rejected []RejectedCandidate
+	seen := make(map[string]string) // Content hash to the URL kept for it
+	for _, slot := range slots {
+		if slot == nil {
+			continue
+		}
+		if slot.rejected != nil {
+			rejected = append(rejected, *slot.rejected)
+			continue
+		}
+		logo := slot.logo
+		if logo.ContentHash != "" {
+			if keptURL, ok := seen[logo.ContentHash]; ok {
+				rejected = append(rejected, RejectedCandidate{
+					URL:    logo.URL,
+					Source: logo.Source,
+					Reason: "duplicate of " + keptURL,
+				})
+				continue
+			}
+			seen[logo.ContentHash] = logo.URL
+		}
 		valid = append(valid, logo)
+		valid = append(valid, slot.variants...)
 	}
 
-	return valid
+	return valid, rejected
 }
 
 // validateSingleLogo validates a single logo URL
-func (lv *LogoValidator) validateSingleLogo(ctx context.Context, url string, results chan<- LogoInfo, wg *sync.WaitGroup) {
+func (lv *LogoValidator) validateSingleLogo(ctx context.Context, index int, candidate Candidate, prefs config.Preferences, results chan<- validatedLogo, wg *sync.WaitGroup) {
 	defer wg.Done()
 
+	// Runs on its own goroutine, out of reach of the worker's recover: a
+	// panic (e.g. in an image decoder) rejects just this candidate
+	defer func() {
+		if r := recover(); r != nil {
+			lv.logger.Error("candidate validation panicked", "url", candidate.URL, "panic", r, "stack", string(debug.Stack()))
+			results <- validatedLogo{index: index, rejected: &RejectedCandidate{
+				URL:    candidate.URL,
+				Source: candidate.Source,
+				Reason: fmt.Sprintf("panic: %v", r),
+			}}
+		}
+	}()
+
 	select {
 	case lv.semaphore <- struct{}{}:
 		defer func() { <-lv.semaphore }()
 	case <-ctx.Done():
+		results <- validatedLogo{index: index, rejected: &RejectedCandidate{
+			URL:    candidate.URL,
+			Source: candidate.Source,
+			Reason: rejectionReason(ctx.Err()),
+		}}
+		return
+	}
+
+	probe, err := lv.cachedProbeImage(ctx, candidate.URL, prefs)
+	if prefs.Validation.RetryTruncated && isTruncatedProbe(candidate.URL, probe, err) && ctx.Err() == nil {
+		lv.logger.Debug("retrying truncated image", "url", candidate.URL, "error", err)
+		probe, err = lv.cachedProbeImage(ctx, candidate.URL, prefs)
+	}
+	if err == nil && !formatAllowed(probe.Format, prefs.Validation.AllowedFormats) {
+		err = fmt.Errorf("format %s not allowed", probe.Format)
+	}
+	if err == nil && (probe.Width <= 0 || probe.Height <= 0) {
+		err = fmt.Errorf("dimensions %dx%d", probe.Width, probe.Height)
+	}
+	if err == nil {
+		err = lv.checkDimensionLimits(probe.Width, probe.Height, prefs)
+	}
+	if err != nil {
+		reason := rejectionReason(err)
+		lv.logger.Debug("candidate rejected", "url", candidate.URL, "source", candidate.Source, "reason", reason)
+		results <- validatedLogo{index: index, rejected: &RejectedCandidate{
+			URL:    candidate.URL,
+			Source: candidate.Source,
+			Reason: reason,
+		}}
 		return
 	}
 
-	width, height := lv.getImageDimensionsWithContext(ctx, url)
-	if width > 0 && height > 0 {
-		results <- LogoInfo{
-			URL:    url,
-			Width:  width,
-			Height: height,
-			Valid:  true,
+	logo := LogoInfo{
+		URL:            candidate.URL,
+		Width:          probe.Width,
+		Height:         probe.Height,
+		Format:         probe.Format,
+		AspectRatio:    float64(probe.Width) / float64(probe.Height),
+		HasAlpha:       probe.HasAlpha,
+		Background:     probe.Background,
+		Valid:          true,
+		Source:         candidate.Source,
+		Position:       candidate.Position,
+		DeclaredWidth:  candidate.DeclaredWidth,
+		DeclaredHeight: candidate.DeclaredHeight,
+		Scalable:       candidate.Scalable,
+		Alt:            candidate.Alt,
+		ContentHash:    probe.Hash,
+	}
+	variants := lv.icoVariants(logo, probe.Sizes, prefs)
+	if prefs.Validation.VerifyDeclaredSize {
+		lv.checkDeclaredSize(&logo, prefs.Validation.DeclaredSizeTolerance)
+	}
+	results <- validatedLogo{index: index, logo: logo, variants: variants}
+}
+
+// icoVariants returns a logo for each image of a multi-image ICO besides the
+// largest one, which logo already describes. Their URLs carry a "#WxH"
+// fragment so they stay distinct in reports; the fragment is never sent to
+// the server. The page's declared size describes the whole file, so it is
+// not carried over. Sizes outside the dimension limits are left out, as are
+// data: URIs and URLs that already have a fragment.
+func (lv *LogoValidator) icoVariants(logo LogoInfo, sizes []image.Point, prefs config.Preferences) []LogoInfo {
+	if len(sizes) < 2 || utils.IsDataURI(logo.URL) || strings.Contains(logo.URL, "#") {
+		return nil
+	}
+
+	var variants []LogoInfo
+	for _, size := range sizes[1:] {
+		if lv.checkDimensionLimits(size.X, size.Y, prefs) != nil {
+			continue
+		}
+		variant := logo
+		variant.URL = fmt.Sprintf("%s#%dx%d", logo.URL, size.X, size.Y)
+		variant.Width, variant.Height = size.X, size.Y
+		variant.AspectRatio = float64(size.X) / float64(size.Y)
+		variant.DeclaredWidth, variant.DeclaredHeight = 0, 0
+		variant.Warnings = nil
+		variants = append(variants, variant)
+	}
+	return variants
+}
+
+// checkDimensionLimits enforces the preferred maximum size and, in strict
+// mode, the minimum size as hard filters
+func (lv *LogoValidator) checkDimensionLimits(width, height int, prefs config.Preferences) error {
+	preferred := prefs.Preferred
+	if (preferred.MaxWidth > 0 && width > preferred.MaxWidth) || (preferred.MaxHeight > 0 && height > preferred.MaxHeight) {
+		return fmt.Errorf("dimensions %dx%d above max %dx%d", width, height, preferred.MaxWidth, preferred.MaxHeight)
+	}
+	if preferred.Strict && (width < preferred.MinWidth || height < preferred.MinHeight) {
+		return fmt.Errorf("dimensions %dx%d below min %dx%d", width, height, preferred.MinWidth, preferred.MinHeight)
+	}
+	return nil
+}
+
+// rejectionReason turns a validation error into a short reason, collapsing
+// the various timeout errors into "timeout"
+func rejectionReason(err error) string {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "timeout"
+	}
+	return err.Error()
+}
+
+// checkDeclaredSize records a warning when the decoded dimensions differ from
+// the declared ones by more than the given relative tolerance
+func (lv *LogoValidator) checkDeclaredSize(logo *LogoInfo, tolerance float64) {
+	exceeds := func(declared, decoded int) bool {
+		if declared <= 0 {
+			return false
+		}
+		diff := float64(decoded-declared) / float64(declared)
+		return diff > tolerance || diff < -tolerance
+	}
+
+	if exceeds(logo.DeclaredWidth, logo.Width) || exceeds(logo.DeclaredHeight, logo.Height) {
+		logo.Warnings = append(logo.Warnings, fmt.Sprintf("declared %dx%d but decoded %dx%d",
+			logo.DeclaredWidth, logo.DeclaredHeight, logo.Width, logo.Height))
+	}
+}
+
+// cachedProbeImage is probeImage, served from the validation cache when it
+// holds a fresh result for url
+func (lv *LogoValidator) cachedProbeImage(ctx context.Context, url string, prefs config.Preferences) (imageProbe, error) {
+	if lv.cache == nil || utils.IsDataURI(url) {
+		return lv.probeImage(ctx, url, prefs)
+	}
+	if cached, ok := lv.cache.get(url); ok {
+		return cached.probe, cached.err
+	}
+
+	probe, err := lv.probeImage(ctx, url, prefs)
+	if isTruncatedProbe(url, probe, err) {
+		return probe, err // Not worth remembering; the next fetch may succeed
+	}
+	lv.cache.put(url, probe, err)
+	return probe, err
+}
+
+// errShortBody reports an image body that ended before the image did,
+// usually a dropped connection rather than a broken image
+var errShortBody = errors.New("image body cut short")
+
+// isTruncatedProbe reports whether a probe of url looks cut short by a flaky
+// connection: its body ended early, or a raster image decoded to 0x0. SVGs
+// without a size and data: URIs give the same result every time.
+func isTruncatedProbe(url string, probe imageProbe, err error) bool {
+	if utils.IsDataURI(url) {
+		return false
+	}
+	if err != nil {
+		return errors.Is(err, errShortBody)
+	}
+	return probe.Format != "svg" && (probe.Width <= 0 || probe.Height <= 0)
+}
+
+// imageProbe describes an image fetched during validation
+type imageProbe struct {
+	Width    int
+	Height   int
+	Format   string // Decoder name: png, jpeg, gif, webp, ico or svg
+	HasAlpha bool   // PNG or WebP with an alpha channel or transparent palette
+	Hash     string // Hex SHA-256 of the body, empty if it could not be read fully
+	// Background is the corner color of small raster images fetched whole
+	// (see LogoInfo.Background)
+	Background string
+	// Sizes lists every size embedded in an ICO, largest first; Width and
+	// Height hold the largest
+	Sizes []image.Point
+}
+
+// probeRangeBytes is how much of an image a ranged probe asks for, enough to
+// decode the dimensions of nearly every image
+const probeRangeBytes = 64 << 10
+
+// errTruncatedProbe reports that a ranged response ended before the image
+// header could be decoded
+var errTruncatedProbe = errors.New("image header beyond ranged probe")
+
+// probeImage fetches url and decodes its dimensions and format, along with
+// the hex SHA-256 of the response body. It first asks for the leading
+// probeRangeBytes only and falls back to a full GET when that is not enough.
+func (lv *LogoValidator) probeImage(ctx context.Context, url string, prefs config.Preferences) (imageProbe, error) {
+	if utils.IsDataURI(url) {
+		return lv.probeDataURI(url, prefs)
+	}
+
+	maxBytes := prefs.Validation.MaxImageBytes
+	if maxBytes > 0 && prefs.Validation.HeadCheck {
+		if size, ok := lv.headContentLength(ctx, url, prefs.Validation.Accept); ok && size > maxBytes {
+			return imageProbe{}, fmt.Errorf("too large: %d bytes", size)
 		}
 	}
+
+	// Clearbit placeholder detection needs the whole image
+	probe, err := lv.fetchProbe(ctx, url, prefs, !isClearbitURL(url))
+	if errors.Is(err, errTruncatedProbe) {
+		return lv.fetchProbe(ctx, url, prefs, false)
+	}
+	return probe, err
 }
 
-// getImageDimensionsWithContext gets image dimensions with context
-func (lv *LogoValidator) getImageDimensionsWithContext(ctx context.Context, url string) (int, int) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// fetchProbe is one probeImage attempt. When ranged, it sends a Range header
+// and decodes from a 206 response; servers that ignore ranges answer 200 with
+// the full body, which is handled as an ordinary GET.
+func (lv *LogoValidator) fetchProbe(ctx context.Context, url string, prefs config.Preferences, ranged bool) (imageProbe, error) {
+	maxBytes := prefs.Validation.MaxImageBytes
+
+	req, err := utils.NewRequestWithContext(ctx, http.MethodGet, url)
 	if err != nil {
-		return 0, 0
+		return imageProbe{}, err
+	}
+	if ranged {
+		req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", probeRangeBytes-1))
+	}
+	if prefs.Validation.Accept != "" {
+		req.Header.Set("Accept", prefs.Validation.Accept)
 	}
 
-	resp, err := utils.Client.Do(req)
+	resp, err := lv.client.Do(req)
 	if err != nil {
-		return 0, 0
+		return imageProbe{}, err
 	}
 	defer resp.Body.Close()
 
-	img, _, err := image.DecodeConfig(resp.Body)
+	switch {
+	case ranged && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		return imageProbe{}, errTruncatedProbe
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return imageProbe{}, permanent("http %d", resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return imageProbe{}, fmt.Errorf("http %d", resp.StatusCode)
+	}
+
+	// A 206 only holds the start of the image; Content-Range has its full size
+	size, partial := resp.ContentLength, false
+	if resp.StatusCode == http.StatusPartialContent {
+		total, known := contentRangeTotal(resp.Header.Get("Content-Range"))
+		if known {
+			size = total
+		}
+		partial = !known || total > probeRangeBytes
+	}
+	if maxBytes > 0 && size > maxBytes {
+		return imageProbe{}, fmt.Errorf("too large: %d bytes", size)
+	}
+
+	// Skip non-image responses without decoding them. A missing Content-Type
+	// still gets a decode attempt.
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" &&
+		!strings.HasPrefix(strings.ToLower(strings.TrimSpace(contentType)), "image/") {
+		return imageProbe{}, permanent("unexpected content type %q", contentType)
+	}
+
+	// Content-Length may be missing or wrong; cap what is actually read
+	var src io.Reader = resp.Body
+	if prefs.Validation.MaxReadBytes > 0 {
+		src = utils.NewLimitedReader(resp.Body, prefs.Validation.MaxReadBytes)
+	}
+
+	// Clearbit images are small; buffer them to check for its placeholder
+	var raw []byte
+	if isClearbitURL(url) {
+		limited := src
+		if maxBytes > 0 {
+			limited = io.LimitReader(src, maxBytes)
+		}
+		if raw, err = io.ReadAll(limited); err != nil {
+			return imageProbe{}, err
+		}
+		src = bytes.NewReader(raw)
+	}
+
+	// Hash everything read from the body so mirrored copies can be dropped.
+	// Whole bodies of small images are also kept to read their background.
+	hasher := sha256.New()
+	whole := &cappedBuffer{max: backgroundMaxBytes}
+	tee := io.Writer(hasher)
+	if !partial {
+		tee = io.MultiWriter(hasher, whole)
+	}
+	body := bufio.NewReader(io.TeeReader(src, tee))
+
+	probe, err := decodeImageConfig(body)
+	if err != nil {
+		if partial {
+			return imageProbe{}, errTruncatedProbe
+		}
+		if errors.Is(err, utils.ErrReadLimitExceeded) {
+			return imageProbe{}, fmt.Errorf("decode failed: over %d bytes read", prefs.Validation.MaxReadBytes)
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return imageProbe{}, fmt.Errorf("%w: %w", errShortBody, err)
+		}
+		return imageProbe{}, permanent("decode failed: %w", err)
+	}
+
+	// Filter disallowed formats before downloading the rest of the image
+	if !formatAllowed(probe.Format, prefs.Validation.AllowedFormats) {
+		return imageProbe{}, fmt.Errorf("format %s not allowed", probe.Format)
+	}
+
+	if raw != nil && probe.Format != "svg" && isPlaceholderImage(raw) {
+		return imageProbe{}, permanent("clearbit placeholder image")
+	}
+
+	// Read the rest of the image so the hash covers the whole body
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return probe, nil
+	}
+	// A partial body is told apart from other images sharing its first bytes
+	// by the full size
+	if partial {
+		fmt.Fprintf(hasher, "/%d", size)
+	} else {
+		probe.Background = imageBackground(whole.Bytes(), probe)
+	}
+	probe.Hash = hex.EncodeToString(hasher.Sum(nil))
+	return probe, nil
+}
+
+// decodeImageConfig decodes the format and dimensions at the start of body
+func decodeImageConfig(body *bufio.Reader) (imageProbe, error) {
+	var probe imageProbe
+	var err error
+
+	// image.DecodeConfig cannot read SVGs, so parse their root element instead
+	head, _ := body.Peek(512)
+	if looksLikeSVG(head) {
+		probe.Format = "svg"
+		probe.Width, probe.Height, err = decodeSVGConfig(body)
+	} else {
+		// The directory is read from head before decoding moves past it
+		if bytes.HasPrefix(head, []byte(icoMagic)) {
+			probe.Sizes = icoSizes(head)
+		}
+		var img image.Config
+		img, probe.Format, err = image.DecodeConfig(body)
+		probe.Width, probe.Height = img.Width, img.Height
+		if probe.Format == "png" || probe.Format == "webp" {
+			probe.HasAlpha = colorModelHasAlpha(img.ColorModel)
+		}
+	}
+	return probe, err
+}
+
+// probeDataURI decodes an inline data: URI image without any request
+func (lv *LogoValidator) probeDataURI(uri string, prefs config.Preferences) (imageProbe, error) {
+	mediaType, data, err := utils.DecodeDataURI(uri)
+	if err != nil {
+		return imageProbe{}, permanent("invalid data URI: %w", err)
+	}
+	if !strings.HasPrefix(mediaType, "image/") {
+		return imageProbe{}, permanent("unexpected content type %q", mediaType)
+	}
+	if maxBytes := prefs.Validation.MaxImageBytes; maxBytes > 0 && int64(len(data)) > maxBytes {
+		return imageProbe{}, fmt.Errorf("too large: %d bytes", len(data))
+	}
+
+	probe, err := decodeImageConfig(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return imageProbe{}, permanent("decode failed: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	probe.Hash = hex.EncodeToString(sum[:])
+	probe.Background = imageBackground(data, probe)
+	return probe, nil
+}
+
+// contentRangeTotal returns the complete length from a Content-Range header
+// such as "bytes 0-65535/1048576"; ok is false when it is missing or "*"
+func contentRangeTotal(contentRange string) (total int64, ok bool) {
+	_, totalStr, found := strings.Cut(contentRange, "/")
+	if !found {
+		return 0, false
+	}
+	total, err := strconv.ParseInt(strings.TrimSpace(totalStr), 10, 64)
+	if err != nil || total < 0 {
+		return 0, false
+	}
+	return total, true
+}
+
+// isClearbitURL reports whether rawURL points at the Clearbit logo API
+func isClearbitURL(rawURL string) bool {
+	u, err := neturl.Parse(rawURL)
+	return err == nil && strings.EqualFold(u.Hostname(), clearbitHost)
+}
+
+// placeholderMaxSpread is the largest luminance range (0-255) of an image
+// still treated as a flat gray placeholder
+const placeholderMaxSpread = 64
+
+// isPlaceholderImage reports whether raw decodes to a gray image without
+// real contrast, like the generic image Clearbit serves for unknown domains.
+// Grayscale logos still pass thanks to their dark-on-light contrast.
+func isPlaceholderImage(raw []byte) bool {
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return false
+	}
+
+	// Sample a grid rather than every pixel
+	bounds := img.Bounds()
+	stepX := max(1, bounds.Dx()/32)
+	stepY := max(1, bounds.Dy()/32)
+	minLum, maxLum := 255, 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 0x80 {
+				continue // Transparent padding says nothing about the content
+			}
+			r, g, b := int(c.R), int(c.G), int(c.B)
+			if max(r, g, b)-min(r, g, b) > 16 {
+				return false // Colored pixels: a real logo
+			}
+			lum := (r + g + b) / 3
+			minLum, maxLum = min(minLum, lum), max(maxLum, lum)
+		}
+	}
+	return maxLum-minLum <= placeholderMaxSpread
+}
+
+// Limits on the images whose background is read, since that needs a full
+// decode
+const (
+	backgroundMaxBytes  = 512 << 10
+	backgroundMaxPixels = 1024 * 1024
+)
+
+// Background values of LogoInfo
+const (
+	BackgroundLight       = config.BackgroundLight
+	BackgroundDark        = config.BackgroundDark
+	BackgroundTransparent = "transparent"
+)
+
+// imageBackground decodes the raster image in data, described by probe, and
+// returns its corner background; "" for SVGs, images too large to decode
+// cheaply or data that does not decode
+func imageBackground(data []byte, probe imageProbe) string {
+	if data == nil || probe.Format == "svg" || probe.Width*probe.Height > backgroundMaxPixels {
+		return ""
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
-		return 0, 0
+		return ""
+	}
+	return cornerBackground(img)
+}
+
+// cornerBackground classifies the background of img from its four corner
+// pixels: transparent, light or dark when at least three corners agree, ""
+// otherwise
+func cornerBackground(img image.Image) string {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return ""
+	}
+
+	corners := []image.Point{
+		{bounds.Min.X, bounds.Min.Y}, {bounds.Max.X - 1, bounds.Min.Y},
+		{bounds.Min.X, bounds.Max.Y - 1}, {bounds.Max.X - 1, bounds.Max.Y - 1},
+	}
+	counts := make(map[string]int)
+	for _, corner := range corners {
+		c := color.NRGBAModel.Convert(img.At(corner.X, corner.Y)).(color.NRGBA)
+		switch {
+		case c.A < 0x80:
+			counts[BackgroundTransparent]++
+		case 299*int(c.R)+587*int(c.G)+114*int(c.B) >= 128*1000: // Perceived luminance
+			counts[BackgroundLight]++
+		default:
+			counts[BackgroundDark]++
+		}
+	}
+	for background, n := range counts {
+		if n >= 3 {
+			return background
+		}
+	}
+	return ""
+}
+
+// cappedBuffer keeps everything written to it up to max bytes, and nothing
+// once more was written. Writes never fail.
+type cappedBuffer struct {
+	buf      bytes.Buffer
+	max      int
+	overflow bool
+}
+
+func (cb *cappedBuffer) Write(p []byte) (int, error) {
+	if !cb.overflow && cb.buf.Len()+len(p) <= cb.max {
+		cb.buf.Write(p)
+	} else if !cb.overflow {
+		cb.overflow = true
+		cb.buf = bytes.Buffer{}
+	}
+	return len(p), nil
+}
+
+// Bytes returns the bytes written, or nil after an overflow
+func (cb *cappedBuffer) Bytes() []byte {
+	if cb.overflow {
+		return nil
+	}
+	return cb.buf.Bytes()
+}
+
+// colorModelHasAlpha reports whether images in model can be transparent
+func colorModelHasAlpha(model color.Model) bool {
+	switch model {
+	case color.NRGBAModel, color.RGBAModel, color.NRGBA64Model, color.RGBA64Model,
+		color.AlphaModel, color.Alpha16Model, color.NYCbCrAModel:
+		return true
+	}
+	if palette, ok := model.(color.Palette); ok {
+		for _, c := range palette {
+			if _, _, _, a := c.RGBA(); a < 0xffff {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// formatAllowed reports whether format is in allowed, accepting everything
+// when allowed is empty. Entries are case-insensitive, may carry a leading
+// dot and "jpg" matches "jpeg".
+func formatAllowed(format string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, entry := range allowed {
+		entry = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(entry)), ".")
+		if entry == "jpg" {
+			entry = "jpeg"
+		}
+		if entry == format {
+			return true
+		}
+	}
+	return false
+}
+
+// headContentLength sends a HEAD request for url and returns its
+// Content-Length. ok is false when the server rejects HEAD or omits the
+// length, in which case the caller falls back to the GET.
+func (lv *LogoValidator) headContentLength(ctx context.Context, url, accept string) (size int64, ok bool) {
+	req, err := utils.NewRequestWithContext(ctx, http.MethodHead, url)
+	if err != nil {
+		return 0, false
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept) // The negotiated variant's size
+	}
+
+	resp, err := lv.client.Do(req)
+	if err != nil {
+		return 0, false
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || resp.ContentLength < 0 {
+		return 0, false
 	}
-	return img.Width, img.Height
+	return resp.ContentLength, true
 }
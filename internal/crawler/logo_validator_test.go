@@ -0,0 +1,106 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/config"
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
+)
+
+// countingDoer counts the requests sent through it
+type countingDoer struct {
+	doer     utils.Doer
+	requests atomic.Int64
+}
+
+// Do implements utils.Doer
+func (cd *countingDoer) Do(req *http.Request) (*http.Response, error) {
+	cd.requests.Add(1)
+	return cd.doer.Do(req)
+}
+
+// encodePNG returns a blank PNG of the given size
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// discardLogger returns a logger that drops all output
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestLogoValidatorValidateConcurrently(t *testing.T) {
+	logo := encodePNG(t, 64, 64)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/logo.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(logo)
+	})
+	mux.HandleFunc("/page.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, "<html><body>not an image</body></html>")
+	})
+	mux.HandleFunc("/corrupt.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		io.WriteString(w, "not a png")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		wantValid  bool
+		wantReason string // Prefix of the rejection reason
+	}{
+		{name: "png", path: "/logo.png", wantValid: true},
+		{name: "not found", path: "/missing.png", wantReason: "http 404"},
+		{name: "html page", path: "/page.html", wantReason: "unexpected content type"},
+		{name: "corrupt image", path: "/corrupt.png", wantReason: "decode failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doer := &countingDoer{doer: srv.Client()}
+			validator := NewLogoValidator(4, 5*time.Second, doer, discardLogger())
+
+			candidates := []Candidate{{URL: srv.URL + tt.path, Source: SourceLink}}
+			valid, rejected := validator.ValidateConcurrently(context.Background(), candidates, config.DefaultPreferences())
+
+			if doer.requests.Load() == 0 {
+				t.Fatal("no request went through the injected Doer")
+			}
+			if tt.wantValid {
+				if len(valid) != 1 || len(rejected) != 0 {
+					t.Fatalf("got %d valid, %d rejected %v; want 1 valid", len(valid), len(rejected), rejected)
+				}
+				if got := valid[0]; got.Width != 64 || got.Height != 64 || got.Format != "png" {
+					t.Errorf("got %dx%d %s, want 64x64 png", got.Width, got.Height, got.Format)
+				}
+				return
+			}
+			if len(valid) != 0 || len(rejected) != 1 {
+				t.Fatalf("got %d valid, %d rejected; want 1 rejected", len(valid), len(rejected))
+			}
+			if reason := rejected[0].Reason; !strings.HasPrefix(reason, tt.wantReason) {
+				t.Errorf("rejection reason = %q, want prefix %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
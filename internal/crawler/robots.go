@@ -0,0 +1,193 @@
+package crawler
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
+)
+
+// robotsUserAgent is the product token matched against robots.txt groups
+const robotsUserAgent = "logo-crawler"
+
+// robotsRule is a single Allow/Disallow line
+type robotsRule struct {
+	allow bool
+	path  string
+}
+
+// robotsRules holds the rules that apply to our user agent for one host
+type robotsRules []robotsRule
+
+// Allowed reports whether path may be fetched. The longest matching rule
+// wins and Allow wins ties, as in RFC 9309.
+func (rr robotsRules) Allowed(path string) bool {
+	allowed := true
+	longest := -1
+	for _, rule := range rr {
+		if !robotsPathMatches(rule.path, path) {
+			continue
+		}
+		if len(rule.path) > longest || (len(rule.path) == longest && rule.allow) {
+			longest = len(rule.path)
+			allowed = rule.allow
+		}
+	}
+	return allowed
+}
+
+// robotsPathMatches matches a robots.txt path pattern, supporting the
+// "*" wildcard and "$" end anchor
+func robotsPathMatches(pattern, path string) bool {
+	if pattern == "" {
+		return false
+	}
+
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	if len(parts) == 1 {
+		return !anchored || rest == ""
+	}
+
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(rest, part)
+		if index < 0 {
+			return false
+		}
+		rest = rest[index+len(part):]
+	}
+
+	last := parts[len(parts)-1]
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
+
+// parseRobots extracts the rules applying to userAgent, falling back to
+// the "*" group when no group names it
+func parseRobots(r io.Reader, userAgent string) robotsRules {
+	var specific, wildcard robotsRules
+	var hasSpecific bool
+
+	var groupAgents []string
+	inRules := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// A user-agent line after rules starts a new group
+			if inRules {
+				groupAgents = nil
+				inRules = false
+			}
+			// An empty name would match every user agent below
+			if value != "" {
+				groupAgents = append(groupAgents, strings.ToLower(value))
+			}
+		case "allow", "disallow":
+			inRules = true
+			rule := robotsRule{allow: key == "allow", path: value}
+			for _, agent := range groupAgents {
+				switch {
+				case agent == "*":
+					wildcard = append(wildcard, rule)
+				case strings.Contains(userAgent, agent):
+					specific = append(specific, rule)
+					hasSpecific = true
+				}
+			}
+		}
+	}
+
+	if hasSpecific {
+		return specific
+	}
+	return wildcard
+}
+
+// robotsCache fetches and caches robots.txt rules per host. The apex and
+// www variants of a domain share one entry.
+type robotsCache struct {
+	client  utils.Doer
+	mu      sync.Mutex
+	entries map[string]*robotsEntry
+}
+
+// robotsEntry holds one host's rules, fetched at most once
+type robotsEntry struct {
+	once  sync.Once
+	rules robotsRules
+}
+
+// newRobotsCache creates a robots.txt cache using the given client
+func newRobotsCache(client utils.Doer) *robotsCache {
+	return &robotsCache{
+		client:  client,
+		entries: make(map[string]*robotsEntry),
+	}
+}
+
+// Allowed reports whether our user agent may fetch path on the given
+// scheme and host. Missing or unreachable robots.txt files allow everything.
+func (rc *robotsCache) Allowed(ctx context.Context, scheme, host, path string) bool {
+	key := strings.TrimPrefix(strings.ToLower(host), "www.")
+
+	rc.mu.Lock()
+	entry, ok := rc.entries[key]
+	if !ok {
+		entry = &robotsEntry{}
+		rc.entries[key] = entry
+	}
+	rc.mu.Unlock()
+
+	// Lookups for the same host wait for a single fetch; other hosts are
+	// not held up by it
+	entry.once.Do(func() {
+		entry.rules = rc.fetch(ctx, scheme+"://"+host+"/robots.txt")
+	})
+	return entry.rules.Allowed(path)
+}
+
+// fetch downloads and parses a robots.txt file
+func (rc *robotsCache) fetch(ctx context.Context, robotsURL string) robotsRules {
+	req, err := utils.NewRequestWithContext(ctx, http.MethodGet, robotsURL)
+	if err != nil {
+		return nil
+	}
+
+	resp, err := rc.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	// Cap the size like major crawlers do (500 KiB)
+	return parseRobots(io.LimitReader(resp.Body, 500*1024), robotsUserAgent)
+}
@@ -0,0 +1,187 @@
+package crawler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/config"
+)
+
+// Logo selection policy presets
+const (
+	// PolicyDefault keeps the built-in balanced weights
+	PolicyDefault = "default"
+	// PolicyOfficial favors logos published by the site or a logo provider:
+	// Clearbit, manifest icons, declared link icons, favicons and
+	// apple-touch-icons
+	PolicyOfficial = "official"
+	// PolicyLargest favors the largest image, dropping the penalty for big
+	// images and breaking ties by area
+	PolicyLargest = "largest"
+	// PolicyCompatible favors widely supported raster formats (PNG, ICO)
+	// over SVG
+	PolicyCompatible = "compatible"
+)
+
+// TieBreak values for ScoringWeights
+const (
+	TieBreakFirst = "first" // Keep the first logo found with the best score
+	TieBreakArea  = "area"  // Prefer the larger logo among equal scores
+)
+
+// ScoringWeights holds the bonuses and penalties applied by BestLogoSelector
+type ScoringWeights struct {
+	MeetsMinimum     int // Logo meets the preferred minimum size
+	BelowMinimum     int // Logo is smaller than the preferred minimum size
+	Clearbit         int
+	Favicon          int
+	AppleTouchIcon   int
+	BrandName        int // Publisher's brand name in the image path or alt text
+	AltLogo          int // Alt text mentions "logo"
+	BackgroundMatch  int // Background matches a light or dark prefer_background
+	Transparent      int // Transparent corners under a light or dark prefer_background
+	SVG              int
+	PNG              int
+	ICO              int
+	LinkIcon         int // Icon declared through a <link rel="icon"> tag
+	ManifestIcon     int // Icon declared in the web app manifest
+	ScalableIcon     int // Icon declared with sizes="any"
+	Dashboard        int // Dashboard, cover or hero image
+	SocialMedia      int // og:image / twitter:image style sharing image
+	Partner          int // Partner or third-party logo
+	Advertisement    int
+	Square           int
+	ReasonableAspect int // Aspect ratio between 1:2 and 2:1
+	MediumSize       int // Area between 100x100 and ~316x316
+	SmallSize        int // Area between ~32x32 and 100x100
+	LargeSize        int // Area above ~316x316
+	TinyImage        int // Either side below 32 pixels
+	EarlyPosition    int // Max bonus for img tags early in the page (low weight)
+	TieBreak         string
+}
+
+// DefaultScoringWeights returns the built-in weights
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{
+		MeetsMinimum:     10,
+		BelowMinimum:     -20,
+		Clearbit:         15,
+		Favicon:          12,
+		AppleTouchIcon:   10,
+		BrandName:        10,
+		AltLogo:          6,
+		BackgroundMatch:  8,
+		Transparent:      -5,
+		SVG:              8,
+		PNG:              3,
+		ICO:              0,
+		LinkIcon:         0,
+		ManifestIcon:     0,
+		ScalableIcon:     8,
+		Dashboard:        -30,
+		SocialMedia:      -25,
+		Partner:          -40,
+		Advertisement:    -35,
+		Square:           5,
+		ReasonableAspect: 3,
+		MediumSize:       8,
+		SmallSize:        5,
+		LargeSize:        -10,
+		TinyImage:        -15,
+		EarlyPosition:    2,
+		TieBreak:         TieBreakFirst,
+	}
+}
+
+// WeightsForPolicy returns the scoring weights for a named policy preset.
+// An empty policy selects the default weights.
+func WeightsForPolicy(policy string) (ScoringWeights, error) {
+	weights := DefaultScoringWeights()
+
+	switch policy {
+	case "", PolicyDefault:
+	case PolicyOfficial:
+		weights.Clearbit = 25
+		weights.Favicon = 15
+		weights.AppleTouchIcon = 15
+		weights.LinkIcon = 10
+		weights.ManifestIcon = 12
+		weights.SocialMedia = -35
+	case PolicyLargest:
+		weights.MediumSize = 5
+		weights.SmallSize = 0
+		weights.LargeSize = 10
+		weights.Dashboard = -10
+		weights.TieBreak = TieBreakArea
+	case PolicyCompatible:
+		weights.SVG = -5
+		weights.PNG = 10
+		weights.ICO = 8
+	default:
+		return weights, fmt.Errorf("unknown policy %q (expected %s, %s, %s or %s)",
+			policy, PolicyDefault, PolicyOfficial, PolicyLargest, PolicyCompatible)
+	}
+
+	return weights, nil
+}
+
+// scoringRules maps the rule names accepted in the YAML scoring section to
+// their ScoringWeights fields
+var scoringRules = map[string]func(*ScoringWeights) *int{
+	"meets_minimum":     func(w *ScoringWeights) *int { return &w.MeetsMinimum },
+	"below_minimum":     func(w *ScoringWeights) *int { return &w.BelowMinimum },
+	"clearbit":          func(w *ScoringWeights) *int { return &w.Clearbit },
+	"favicon":           func(w *ScoringWeights) *int { return &w.Favicon },
+	"apple_touch_icon":  func(w *ScoringWeights) *int { return &w.AppleTouchIcon },
+	"brand_name":        func(w *ScoringWeights) *int { return &w.BrandName },
+	"alt_logo":          func(w *ScoringWeights) *int { return &w.AltLogo },
+	"background_match":  func(w *ScoringWeights) *int { return &w.BackgroundMatch },
+	"transparent":       func(w *ScoringWeights) *int { return &w.Transparent },
+	"svg":               func(w *ScoringWeights) *int { return &w.SVG },
+	"png":               func(w *ScoringWeights) *int { return &w.PNG },
+	"ico":               func(w *ScoringWeights) *int { return &w.ICO },
+	"link_icon":         func(w *ScoringWeights) *int { return &w.LinkIcon },
+	"manifest_icon":     func(w *ScoringWeights) *int { return &w.ManifestIcon },
+	"scalable_icon":     func(w *ScoringWeights) *int { return &w.ScalableIcon },
+	"dashboard":         func(w *ScoringWeights) *int { return &w.Dashboard },
+	"social_media":      func(w *ScoringWeights) *int { return &w.SocialMedia },
+	"partner":           func(w *ScoringWeights) *int { return &w.Partner },
+	"advertisement":     func(w *ScoringWeights) *int { return &w.Advertisement },
+	"square":            func(w *ScoringWeights) *int { return &w.Square },
+	"reasonable_aspect": func(w *ScoringWeights) *int { return &w.ReasonableAspect },
+	"medium_size":       func(w *ScoringWeights) *int { return &w.MediumSize },
+	"small_size":        func(w *ScoringWeights) *int { return &w.SmallSize },
+	"large_size":        func(w *ScoringWeights) *int { return &w.LargeSize },
+	"tiny_image":        func(w *ScoringWeights) *int { return &w.TinyImage },
+	"early_position":    func(w *ScoringWeights) *int { return &w.EarlyPosition },
+}
+
+// WeightsForPreferences returns the policy's weights with the preferences'
+// scoring overrides applied. Unknown policies and rule names are errors.
+func WeightsForPreferences(prefs config.Preferences) (ScoringWeights, error) {
+	weights, err := WeightsForPolicy(prefs.Policy)
+	if err != nil {
+		return weights, err
+	}
+
+	for name, value := range prefs.Scoring {
+		field, ok := scoringRules[strings.ToLower(name)]
+		if !ok {
+			return weights, fmt.Errorf("unknown scoring rule %q (expected one of %s)", name, knownScoringRules())
+		}
+		*field(&weights) = value
+	}
+
+	return weights, nil
+}
+
+// knownScoringRules lists the accepted scoring rule names, sorted
+func knownScoringRules() string {
+	names := make([]string, 0, len(scoringRules))
+	for name := range scoringRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,143 @@
+package crawler
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/config"
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
+)
+
+// sitemapPagePatterns match sitemap pages likely to show the brand's logo,
+// in order of preference
+var sitemapPagePatterns = []string{"brand", "press", "media", "about"}
+
+// maxSitemapIndexChildren caps the child sitemaps followed from a sitemap index
+const maxSitemapIndexChildren = 3
+
+// sitemapDocument covers both <urlset> sitemaps and <sitemapindex> indexes
+type sitemapDocument struct {
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// extractFromSitemap finds brand, press, media and about pages in the
+// site's sitemap and extracts candidates from up to SitemapMaxPages of them
+func (le *LogoExtractor) extractFromSitemap(ctx context.Context, baseURL string, prefs config.Preferences) []Candidate {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+
+	pages := le.sitemapPages(ctx, base, prefs.Extraction.SitemapMaxPages)
+
+	var candidates []Candidate
+	for _, page := range pages {
+		le.logger.Debug("extracting from sitemap page", "url", page)
+		pageCandidates, _, _ := le.extractFromSingleURL(ctx, page, prefs)
+		candidates = append(candidates, pageCandidates...)
+	}
+	return le.unique(candidates)
+}
+
+// sitemapPages returns up to limit same-site pages from /sitemap.xml whose
+// URL matches sitemapPagePatterns, most relevant pattern first
+func (le *LogoExtractor) sitemapPages(ctx context.Context, base *url.URL, limit int) []string {
+	if limit <= 0 {
+		return nil
+	}
+
+	doc, ok := le.fetchSitemap(ctx, base.ResolveReference(&url.URL{Path: "/sitemap.xml"}).String())
+	if !ok {
+		return nil
+	}
+
+	locs := make([]string, 0, len(doc.URLs))
+	for _, u := range doc.URLs {
+		locs = append(locs, u.Loc)
+	}
+
+	// Follow a few child sitemaps of an index, one level deep
+	for i, child := range doc.Sitemaps {
+		if i >= maxSitemapIndexChildren {
+			break
+		}
+		if childDoc, ok := le.fetchSitemap(ctx, strings.TrimSpace(child.Loc)); ok {
+			for _, u := range childDoc.URLs {
+				locs = append(locs, u.Loc)
+			}
+		}
+	}
+
+	siteHost := bareHost(base.Hostname())
+	var pages []string
+	seen := make(map[string]bool)
+	for _, pattern := range sitemapPagePatterns {
+		for _, loc := range locs {
+			loc = strings.TrimSpace(loc)
+			u, err := url.Parse(loc)
+			if err != nil || seen[loc] {
+				continue
+			}
+			if bareHost(u.Hostname()) != siteHost {
+				continue
+			}
+			if !strings.Contains(strings.ToLower(u.Path), pattern) {
+				continue
+			}
+			seen[loc] = true
+			pages = append(pages, loc)
+			if len(pages) >= limit {
+				return pages
+			}
+		}
+	}
+	return pages
+}
+
+// fetchSitemap fetches and parses a sitemap, honoring robots.txt
+func (le *LogoExtractor) fetchSitemap(ctx context.Context, sitemapURL string) (sitemapDocument, bool) {
+	var doc sitemapDocument
+
+	u, err := url.Parse(sitemapURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return doc, false
+	}
+	if !le.robots.Allowed(ctx, u.Scheme, u.Host, u.EscapedPath()) {
+		return doc, false
+	}
+
+	req, err := utils.NewRequestWithContext(ctx, http.MethodGet, sitemapURL)
+	if err != nil {
+		return doc, false
+	}
+
+	resp, err := le.client.Do(req)
+	if err != nil {
+		return doc, false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return doc, false
+	}
+
+	body, err := utils.DecodeBody(resp)
+	if err != nil {
+		return doc, false
+	}
+
+	// Large sitemaps are truncated; the first entries are enough to find a few pages
+	if err := xml.NewDecoder(io.LimitReader(body, 5<<20)).Decode(&doc); err != nil && len(doc.URLs) == 0 && len(doc.Sitemaps) == 0 {
+		return doc, false
+	}
+	return doc, true
+}
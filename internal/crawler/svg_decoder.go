@@ -0,0 +1,81 @@
+package crawler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// errNoSVGDimensions is returned when an SVG declares neither a usable
+// width/height pair nor a viewBox
+var errNoSVGDimensions = errors.New("svg has no usable width/height or viewBox")
+
+// looksLikeSVG reports whether the start of a body is an SVG document
+func looksLikeSVG(head []byte) bool {
+	return bytes.Contains(bytes.ToLower(head), []byte("<svg"))
+}
+
+// decodeSVGConfig reads the dimensions of an SVG from its root element,
+// using the width/height attributes when present and the viewBox otherwise
+func decodeSVGConfig(r io.Reader) (int, int, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, 0, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "svg" {
+			continue
+		}
+
+		var width, height float64
+		var viewBox string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "width":
+				width = parseSVGLength(attr.Value)
+			case "height":
+				height = parseSVGLength(attr.Value)
+			case "viewBox":
+				viewBox = attr.Value
+			}
+		}
+
+		if width > 0 && height > 0 {
+			return int(math.Round(width)), int(math.Round(height)), nil
+		}
+
+		// Derive the size from the last two viewBox numbers
+		fields := strings.FieldsFunc(viewBox, func(r rune) bool {
+			return r == ' ' || r == ',' || r == '\t' || r == '\n'
+		})
+		if len(fields) == 4 {
+			vbWidth, errW := strconv.ParseFloat(fields[2], 64)
+			vbHeight, errH := strconv.ParseFloat(fields[3], 64)
+			if errW == nil && errH == nil && vbWidth > 0 && vbHeight > 0 {
+				return int(math.Round(vbWidth)), int(math.Round(vbHeight)), nil
+			}
+		}
+
+		return 0, 0, errNoSVGDimensions
+	}
+}
+
+// parseSVGLength parses an absolute SVG length such as "120" or "120px".
+// Relative units (%, em) cannot be resolved and yield 0.
+func parseSVGLength(value string) float64 {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "px")
+	length, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return length
+}
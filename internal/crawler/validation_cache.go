@@ -0,0 +1,169 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL is how long validation results are reused
+const DefaultCacheTTL = 24 * time.Hour
+
+// ValidationCache stores validation results on disk, one JSON file per URL,
+// so repeated runs skip fetching and decoding logos that were recently seen.
+// Every entry lives for the cache's TTL; HTTP caching headers such as
+// Cache-Control and Expires are ignored. It is safe for concurrent use.
+type ValidationCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// cacheEntry is the on-disk form of a validation result
+type cacheEntry struct {
+	URL        string        `json:"url"`
+	Width      int           `json:"width,omitempty"`
+	Height     int           `json:"height,omitempty"`
+	Format     string        `json:"format,omitempty"`
+	HasAlpha   bool          `json:"has_alpha,omitempty"`
+	Background string        `json:"background,omitempty"`
+	Hash       string        `json:"hash,omitempty"`
+	Sizes      []image.Point `json:"sizes,omitempty"`
+	Error      string        `json:"error,omitempty"` // Permanent rejection reason
+	ExpiresAt  time.Time     `json:"expires_at"`
+}
+
+// permanentError marks validation failures worth caching, such as HTTP 404
+// or an undecodable body, as opposed to timeouts and network errors
+type permanentError struct {
+	err error
+}
+
+func (pe *permanentError) Error() string { return pe.err.Error() }
+func (pe *permanentError) Unwrap() error { return pe.err }
+
+// permanent wraps a formatted error as a permanent validation failure
+func permanent(format string, args ...any) error {
+	return &permanentError{err: fmt.Errorf(format, args...)}
+}
+
+// NewValidationCache creates a cache in dir, creating the directory if
+// needed and deleting expired entries left by earlier runs. A ttl of 0 uses
+// DefaultCacheTTL.
+func NewValidationCache(dir string, ttl time.Duration) (*ValidationCache, error) {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	vc := &ValidationCache{dir: dir, ttl: ttl}
+	vc.prune()
+	return vc, nil
+}
+
+// prune deletes expired and unreadable entries so the directory does not
+// grow without bound across runs
+func (vc *ValidationCache) prune() {
+	paths, err := filepath.Glob(filepath.Join(vc.dir, "*.json"))
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry cacheEntry
+		if json.Unmarshal(data, &entry) != nil || now.After(entry.ExpiresAt) {
+			os.Remove(path)
+		}
+	}
+}
+
+// cachedProbe is a cached validation result: a probe or a permanent rejection
+type cachedProbe struct {
+	probe imageProbe
+	err   error
+}
+
+// get returns the cached result for url; ok is false when there is no fresh
+// entry
+func (vc *ValidationCache) get(url string) (result cachedProbe, ok bool) {
+	data, err := os.ReadFile(vc.path(url))
+	if err != nil {
+		return cachedProbe{}, false
+	}
+
+	var entry cacheEntry
+	if json.Unmarshal(data, &entry) != nil || time.Now().After(entry.ExpiresAt) {
+		os.Remove(vc.path(url)) // Rewritten by put once the URL is validated again
+		return cachedProbe{}, false
+	}
+	if entry.URL != url {
+		return cachedProbe{}, false
+	}
+
+	if entry.Error != "" {
+		return cachedProbe{err: &permanentError{err: errors.New(entry.Error)}}, true
+	}
+	return cachedProbe{probe: imageProbe{
+		Width:      entry.Width,
+		Height:     entry.Height,
+		Format:     entry.Format,
+		HasAlpha:   entry.HasAlpha,
+		Background: entry.Background,
+		Hash:       entry.Hash,
+		Sizes:      entry.Sizes,
+	}}, true
+}
+
+// put stores a validation result for url. Transient failures are not cached.
+func (vc *ValidationCache) put(url string, probe imageProbe, err error) {
+	entry := cacheEntry{
+		URL:        url,
+		Width:      probe.Width,
+		Height:     probe.Height,
+		Format:     probe.Format,
+		HasAlpha:   probe.HasAlpha,
+		Background: probe.Background,
+		Hash:       probe.Hash,
+		Sizes:      probe.Sizes,
+		ExpiresAt:  time.Now().Add(vc.ttl),
+	}
+	if err != nil {
+		var pe *permanentError
+		if !errors.As(err, &pe) {
+			return
+		}
+		entry = cacheEntry{URL: url, Error: err.Error(), ExpiresAt: entry.ExpiresAt}
+	}
+
+	data, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		return
+	}
+
+	// Write to a temporary file and rename so readers never see partial entries
+	tmp, createErr := os.CreateTemp(vc.dir, "entry-*.tmp")
+	if createErr != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), vc.path(url)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// path returns the cache file for url
+func (vc *ValidationCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(vc.dir, hex.EncodeToString(sum[:])+".json")
+}
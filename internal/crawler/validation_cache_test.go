@@ -0,0 +1,46 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestValidationCacheDeletesExpiredEntries(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewValidationCache(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const fresh, stale, reopened = "https://example.com/fresh.png", "https://example.com/stale.png", "https://example.com/reopened.png"
+	cache.put(fresh, imageProbe{Width: 64, Height: 64, Format: "png"}, nil)
+	cache.put(stale, imageProbe{Width: 64, Height: 64, Format: "png"}, nil)
+	cache.put(reopened, imageProbe{Width: 64, Height: 64, Format: "png"}, nil)
+
+	// Entries written by a cache with a TTL that has already run out
+	expired := &ValidationCache{dir: dir, ttl: -time.Minute}
+	expired.put(stale, imageProbe{Width: 64, Height: 64, Format: "png"}, nil)
+	expired.put(reopened, imageProbe{Width: 64, Height: 64, Format: "png"}, nil)
+
+	if _, ok := cache.get(fresh); !ok {
+		t.Errorf("get(%q) missed a fresh entry", fresh)
+	}
+	if _, ok := cache.get(stale); ok {
+		t.Errorf("get(%q) returned an expired entry", stale)
+	}
+	if _, err := os.Stat(cache.path(stale)); !os.IsNotExist(err) {
+		t.Errorf("expired entry for %q still on disk after get: %v", stale, err)
+	}
+
+	if _, err := NewValidationCache(dir, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cache.path(reopened)); !os.IsNotExist(err) {
+		t.Errorf("expired entry for %q still on disk after reopening: %v", reopened, err)
+	}
+	if paths, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(paths) != 1 {
+		t.Errorf("cache holds %d entries, want only the fresh one", len(paths))
+	}
+}
@@ -0,0 +1,100 @@
+package io
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadPublishersCSV reads publishers from a CSV file with a header row,
+// returning the trimmed values of the named column. The column name is
+// matched case-insensitively and empty cells are skipped.
+func ReadPublishersCSV(filePath, column string) ([]string, error) {
+	entries, err := ReadPublisherEntriesCSV(filePath, column, "")
+	if err != nil {
+		return nil, err
+	}
+
+	publishers := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		publishers = append(publishers, entry.Publisher)
+	}
+	return publishers, nil
+}
+
+// ReadPublisherEntriesCSV is ReadPublishersCSV, also taking each publisher's
+// display name from nameColumn when it is set
+func ReadPublisherEntriesCSV(filePath, column, nameColumn string) ([]PublisherEntry, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadPublisherEntriesCSVFromReader(file, column, nameColumn)
+}
+
+// ReadPublisherEntriesCSVFromReader is ReadPublisherEntriesCSV reading from r
+func ReadPublisherEntriesCSVFromReader(r io.Reader, column, nameColumn string) ([]PublisherEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Tolerate rows with missing trailing cells
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("empty CSV file")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columnIndex := headerIndex(header, column)
+	if columnIndex < 0 {
+		return nil, fmt.Errorf("column %q not found in CSV header", column)
+	}
+	nameIndex := -1
+	if nameColumn != "" {
+		if nameIndex = headerIndex(header, nameColumn); nameIndex < 0 {
+			return nil, fmt.Errorf("column %q not found in CSV header", nameColumn)
+		}
+	}
+
+	var entries []PublisherEntry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if columnIndex >= len(record) {
+			continue
+		}
+		value := strings.TrimSpace(record[columnIndex])
+		if value == "" {
+			continue
+		}
+		entry := PublisherEntry{Publisher: value}
+		if nameIndex >= 0 && nameIndex < len(record) {
+			entry.DisplayName = strings.TrimSpace(record[nameIndex])
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// headerIndex returns the index of column in header, matched
+// case-insensitively, or -1 when it is missing
+func headerIndex(header []string, column string) int {
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff") // Byte order mark from spreadsheet exports
+		if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(column)) {
+			return i
+		}
+	}
+	return -1
+}
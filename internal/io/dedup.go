@@ -0,0 +1,42 @@
+package io
+
+import (
+	"strings"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
+)
+
+// ReadPublishersDedup reads publishers like ReadPublishers, dropping entries
+// that resolve to the same domain (e.g. "example.com", "www.example.com" and
+// "https://example.com"). It returns the number of duplicates removed.
+func ReadPublishersDedup(filePath string) ([]string, int, error) {
+	entries, err := ReadPublisherEntries(filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries, duplicates := DedupEntries(entries)
+	publishers := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		publishers = append(publishers, entry.Publisher)
+	}
+	return publishers, duplicates, nil
+}
+
+// DedupEntries keeps the first entry for each domain, as normalized by
+// utils.NormalizeDomain without a leading "www.", preserving input order.
+// It returns the remaining entries and the number of duplicates removed.
+func DedupEntries(entries []PublisherEntry) ([]PublisherEntry, int) {
+	seen := make(map[string]bool, len(entries))
+	unique := make([]PublisherEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		domain := strings.TrimPrefix(utils.NormalizeDomain(entry.Publisher), "www.")
+		if seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		unique = append(unique, entry)
+	}
+	return unique, len(entries) - len(unique)
+}
@@ -2,32 +2,110 @@ package io
 
 import (
 	"bufio"
-	"log"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"strings"
+
+	"golang.org/x/net/http/httpguts"
 )
 
+// PublisherEntry is a single publisher line from the input file
+type PublisherEntry struct {
+	Publisher   string
+	DisplayName string      // Optional friendly name shown in reports
+	Headers     http.Header // Optional headers applied to this publisher's requests
+}
+
 // ReadPublishers reads publishers from a file
 func ReadPublishers(filePath string) ([]string, error) {
+	entries, err := ReadPublisherEntries(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	publishers := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		publishers = append(publishers, entry.Publisher)
+	}
+	return publishers, nil
+}
+
+// ReadPublishersFromReader reads publishers from r, e.g. os.Stdin, in the
+// same format as ReadPublishers
+func ReadPublishersFromReader(r io.Reader) ([]string, error) {
+	entries, err := ReadPublisherEntriesFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	publishers := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		publishers = append(publishers, entry.Publisher)
+	}
+	return publishers, nil
+}
+
+// ReadPublisherEntries reads publishers from a file along with optional
+// display names and per-publisher headers. Each line holds a publisher
+// optionally followed by tab-separated fields: a display name, always the
+// field right after the publisher so that names such as "A=B Media" are kept,
+// then Key=Value header pairs. The display name may be left empty to send
+// headers without one, e.g. "example.com\t\tAuthorization=Bearer xyz".
+func ReadPublisherEntries(filePath string) ([]PublisherEntry, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	var publishers []string
-	scanner := bufio.NewScanner(file)
+	return ReadPublisherEntriesFromReader(file)
+}
+
+// ReadPublisherEntriesFromReader is ReadPublisherEntries reading from r
+func ReadPublisherEntriesFromReader(r io.Reader) ([]PublisherEntry, error) {
+	var entries []PublisherEntry
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
 
 	for scanner.Scan() {
-		publisher := strings.TrimSpace(scanner.Text())
-		if publisher != "" {
-			publishers = append(publishers, publisher)
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
 		}
+
+		fields := strings.Split(line, "\t")
+		entry := PublisherEntry{Publisher: strings.TrimSpace(fields[0])}
+		var headers []string
+		if len(fields) > 1 {
+			entry.DisplayName = strings.TrimSpace(fields[1])
+			headers = fields[2:]
+		}
+
+		for _, field := range headers {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(field, "=")
+			key = strings.TrimSpace(key)
+			if !ok || !httpguts.ValidHeaderFieldName(key) {
+				return nil, fmt.Errorf("line %d: invalid header %q, expected Key=Value", lineNumber, field)
+			}
+			if entry.Headers == nil {
+				entry.Headers = make(http.Header)
+			}
+			entry.Headers.Add(key, strings.TrimSpace(value))
+		}
+
+		entries = append(entries, entry)
 	}
 
 	if err := scanner.Err(); err != nil {
-		log.Fatalf("Error reading file: %v", err)
+		return nil, fmt.Errorf("failed to read publishers: %w", err)
 	}
 
-	return publishers, nil
+	return entries, nil
 }
@@ -0,0 +1,88 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/crawler"
+)
+
+// Checkpoint appends each completed publisher result to a JSON lines file
+// so an interrupted run can resume where it stopped. It is safe for
+// concurrent use.
+type Checkpoint struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	writer *JSONLWriter
+}
+
+// OpenCheckpoint opens the checkpoint at path, creating it if needed, and
+// returns the results it already holds. A last line cut short by a crash is
+// ignored.
+func OpenCheckpoint(path string) (*Checkpoint, []crawler.PublisherResult, error) {
+	results, err := readCheckpoint(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+
+	return &Checkpoint{path: path, file: file, writer: NewJSONLWriter(file)}, results, nil
+}
+
+// readCheckpoint returns the results stored at path, none if it is missing
+func readCheckpoint(path string) ([]crawler.PublisherResult, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	defer file.Close()
+
+	var results []crawler.PublisherResult
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20) // Rejected lists can make long lines
+	for scanner.Scan() {
+		var jr JSONResult
+		if err := json.Unmarshal(scanner.Bytes(), &jr); err != nil || jr.Publisher == "" {
+			continue
+		}
+		results = append(results, jr.PublisherResult())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	return results, nil
+}
+
+// Record appends result to the checkpoint
+func (c *Checkpoint) Record(result crawler.PublisherResult) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.writer.Write(result)
+}
+
+// Close closes the checkpoint file
+func (c *Checkpoint) Close() error {
+	return c.file.Close()
+}
+
+// Remove closes and deletes the checkpoint, once the run it covers is done
+func (c *Checkpoint) Remove() error {
+	c.Close()
+	return os.Remove(c.path)
+}
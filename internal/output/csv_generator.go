@@ -0,0 +1,82 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/crawler"
+)
+
+// csvHeader lists the columns written by CSVGenerator
+var csvHeader = []string{
+	"publisher", "best_logo_url", "best_width", "best_height",
+	"total_logos_found", "duration_ms", "error", "display_name",
+}
+
+// CSVGenerator writes one row per publisher with its best logo
+type CSVGenerator struct {
+	outputPath string
+}
+
+// NewCSVGenerator creates a new CSV generator
+func NewCSVGenerator(outputPath string) *CSVGenerator {
+	return &CSVGenerator{
+		outputPath: outputPath,
+	}
+}
+
+// GenerateReport writes the results as CSV, starting with a header row
+func (cg *CSVGenerator) GenerateReport(results []crawler.PublisherResult) error {
+	dir := filepath.Dir(cg.outputPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	file, err := os.Create(cg.outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, result := range results {
+		if err := writer.Write(csvRow(result)); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV file: %w", err)
+	}
+	return nil
+}
+
+// csvRow converts a publisher result into a CSV row matching csvHeader
+func csvRow(result crawler.PublisherResult) []string {
+	row := []string{
+		result.Publisher, "", "", "",
+		strconv.Itoa(len(result.Logos)),
+		strconv.FormatInt(result.Duration.Milliseconds(), 10),
+		"",
+		result.Name(),
+	}
+
+	if result.Error != nil {
+		row[6] = result.Error.Error()
+		return row
+	}
+	if result.Best != nil {
+		row[1] = result.Best.URL
+		row[2] = strconv.Itoa(result.Best.Width)
+		row[3] = strconv.Itoa(result.Best.Height)
+	}
+	return row
+}
@@ -0,0 +1,252 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
+)
+
+// Publisher change kinds in a ReportDiff
+const (
+	DiffAdded   = "added"   // Only in the new report
+	DiffRemoved = "removed" // Only in the old report
+	DiffChanged = "changed" // In both, with a different best logo or logo set
+)
+
+// PublisherDiff describes how one publisher's logos changed between reports
+type PublisherDiff struct {
+	Publisher string
+	Name      string // Display name from the newer report holding the publisher
+	Kind      string // DiffAdded, DiffRemoved or DiffChanged
+	OldBest   string // Best logo URL in the old report, "" if none
+	NewBest   string // Best logo URL in the new report, "" if none
+	Gained    []string
+	Lost      []string
+}
+
+// BestChanged reports whether the best logo URL differs between the reports
+func (pd PublisherDiff) BestChanged() bool {
+	return pd.OldBest != pd.NewBest
+}
+
+// ReportDiff compares the results of two runs
+type ReportDiff struct {
+	OldPath        string
+	NewPath        string
+	OldGeneratedAt time.Time // Zero for JSON lines files
+	NewGeneratedAt time.Time
+	// Publishers lists the publishers whose logos changed, in new report
+	// order, followed by those only in the old report
+	Publishers []PublisherDiff
+	Unchanged  int
+}
+
+// Count returns how many publishers changed in the given way
+func (rd *ReportDiff) Count(kind string) int {
+	n := 0
+	for _, pd := range rd.Publishers {
+		if pd.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// DiffReports compares two JSON reports, or JSON lines files, of earlier
+// runs. Publishers are matched by their input value and logos by URL.
+func DiffReports(oldPath, newPath string) (*ReportDiff, error) {
+	oldResults, oldGeneratedAt, err := readJSONResults(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newResults, newGeneratedAt, err := readJSONResults(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &ReportDiff{
+		OldPath:        oldPath,
+		NewPath:        newPath,
+		OldGeneratedAt: oldGeneratedAt,
+		NewGeneratedAt: newGeneratedAt,
+	}
+
+	old := make(map[string]JSONResult, len(oldResults))
+	for _, jr := range oldResults {
+		old[jr.Publisher] = jr
+	}
+	seen := make(map[string]bool, len(newResults))
+	for _, jr := range newResults {
+		seen[jr.Publisher] = true
+		previous, found := old[jr.Publisher]
+		if !found {
+			diff.Publishers = append(diff.Publishers, PublisherDiff{
+				Publisher: jr.Publisher,
+				Name:      jr.Name,
+				Kind:      DiffAdded,
+				NewBest:   bestURL(jr),
+				Gained:    logoURLs(jr),
+			})
+			continue
+		}
+
+		pd := PublisherDiff{
+			Publisher: jr.Publisher,
+			Name:      jr.Name,
+			Kind:      DiffChanged,
+			OldBest:   bestURL(previous),
+			NewBest:   bestURL(jr),
+			Gained:    missingFrom(logoURLs(jr), logoURLs(previous)),
+			Lost:      missingFrom(logoURLs(previous), logoURLs(jr)),
+		}
+		if !pd.BestChanged() && len(pd.Gained) == 0 && len(pd.Lost) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Publishers = append(diff.Publishers, pd)
+	}
+
+	for _, jr := range oldResults {
+		if seen[jr.Publisher] {
+			continue
+		}
+		diff.Publishers = append(diff.Publishers, PublisherDiff{
+			Publisher: jr.Publisher,
+			Name:      jr.Name,
+			Kind:      DiffRemoved,
+			OldBest:   bestURL(jr),
+			Lost:      logoURLs(jr),
+		})
+	}
+	return diff, nil
+}
+
+// readJSONResults reads the results of a JSON report, or of a JSON lines
+// file with one result per line. Later duplicates of a publisher win.
+func readJSONResults(path string) ([]JSONResult, time.Time, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to open report: %w", err)
+	}
+	defer file.Close()
+
+	var results []JSONResult
+	var generatedAt time.Time
+	decoder := json.NewDecoder(file)
+	for {
+		// A report object holds results; a JSON lines value is one itself
+		var value struct {
+			JSONResult
+			GeneratedAt time.Time    `json:"generated_at"`
+			Results     []JSONResult `json:"results"`
+		}
+		if err := decoder.Decode(&value); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to parse report %s: %w", path, err)
+		}
+
+		switch {
+		case value.Results != nil:
+			results = append(results, value.Results...)
+			generatedAt = value.GeneratedAt
+		case value.Publisher != "":
+			results = append(results, value.JSONResult)
+		}
+	}
+
+	// Keep the last result of each publisher, e.g. from a retried run
+	last := make(map[string]int, len(results))
+	for i, jr := range results {
+		last[jr.Publisher] = i
+	}
+	deduped := results[:0]
+	for i, jr := range results {
+		if last[jr.Publisher] == i {
+			deduped = append(deduped, jr)
+		}
+	}
+	return deduped, generatedAt, nil
+}
+
+// bestURL returns the URL of a result's best logo, "" if it has none
+func bestURL(jr JSONResult) string {
+	if jr.Best == nil {
+		return ""
+	}
+	return jr.Best.URL
+}
+
+// logoURLs returns the URLs of a result's logos
+func logoURLs(jr JSONResult) []string {
+	urls := make([]string, 0, len(jr.Logos))
+	for _, logo := range jr.Logos {
+		urls = append(urls, logo.URL)
+	}
+	return urls
+}
+
+// missingFrom returns the URLs of urls not in other, in order
+func missingFrom(urls, other []string) []string {
+	var missing []string
+	for _, url := range urls {
+		if !slices.Contains(other, url) {
+			missing = append(missing, url)
+		}
+	}
+	return missing
+}
+
+// WriteSummary writes one line of counts followed by a line per changed
+// publisher
+func (rd *ReportDiff) WriteSummary(w io.Writer) {
+	fmt.Fprintf(w, "🔀 %s → %s: %d changed, %d added, %d removed, %d unchanged\n",
+		rd.OldPath, rd.NewPath, rd.Count(DiffChanged), rd.Count(DiffAdded), rd.Count(DiffRemoved), rd.Unchanged)
+
+	for _, pd := range rd.Publishers {
+		switch pd.Kind {
+		case DiffAdded:
+			fmt.Fprintf(w, "   ➕ %s: new publisher, %s found\n", pd.Publisher, logoCount(len(pd.Gained)))
+		case DiffRemoved:
+			fmt.Fprintf(w, "   ➖ %s: no longer in the report\n", pd.Publisher)
+		default:
+			fmt.Fprintf(w, "   🔄 %s: %s\n", pd.Publisher, pd.changeText())
+		}
+	}
+}
+
+// changeText describes a changed publisher for WriteSummary
+func (pd PublisherDiff) changeText() string {
+	var parts []string
+	switch {
+	case !pd.BestChanged():
+	case pd.OldBest == "":
+		parts = append(parts, "best logo found "+utils.ShortURL(pd.NewBest))
+	case pd.NewBest == "":
+		parts = append(parts, "best logo lost "+utils.ShortURL(pd.OldBest))
+	default:
+		parts = append(parts, "best logo "+utils.ShortURL(pd.OldBest)+" → "+utils.ShortURL(pd.NewBest))
+	}
+	if len(pd.Gained) > 0 {
+		parts = append(parts, logoCount(len(pd.Gained))+" gained")
+	}
+	if len(pd.Lost) > 0 {
+		parts = append(parts, logoCount(len(pd.Lost))+" lost")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// logoCount formats n as "1 logo" or "n logos"
+func logoCount(n int) string {
+	if n == 1 {
+		return "1 logo"
+	}
+	return fmt.Sprintf("%d logos", n)
+}
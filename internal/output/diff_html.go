@@ -0,0 +1,233 @@
+package output
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
+)
+
+// DiffHTMLGenerator renders a ReportDiff as an HTML page showing old and new
+// best logos side by side
+type DiffHTMLGenerator struct {
+	outputPath string
+}
+
+// NewDiffHTMLGenerator creates a new diff HTML generator
+func NewDiffHTMLGenerator(outputPath string) *DiffHTMLGenerator {
+	return &DiffHTMLGenerator{
+		outputPath: outputPath,
+	}
+}
+
+// diffHTMLReport is the data passed to the diff template
+type diffHTMLReport struct {
+	*ReportDiff
+	GeneratedAt time.Time
+	Changed     int
+	Added       int
+	Removed     int
+}
+
+// GenerateReport writes the diff as HTML
+func (dg *DiffHTMLGenerator) GenerateReport(diff *ReportDiff) error {
+	report := diffHTMLReport{
+		ReportDiff:  diff,
+		GeneratedAt: time.Now(),
+		Changed:     diff.Count(DiffChanged),
+		Added:       diff.Count(DiffAdded),
+		Removed:     diff.Count(DiffRemoved),
+	}
+
+	dir := filepath.Dir(dg.outputPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	file, err := os.Create(dg.outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create HTML file: %w", err)
+	}
+	defer file.Close()
+
+	if err := diffTemplate.Execute(file, report); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+// diffTemplate shares the look of the main report
+var diffTemplate = template.Must(template.New("diff").Funcs(template.FuncMap{
+	"logoSrc":  logoSrc,
+	"logoHref": logoHref,
+	"shortURL": utils.ShortURL,
+}).Parse(`
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Logo Crawler Diff</title>
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            line-height: 1.6;
+            margin: 0;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        .container {
+            max-width: 1200px;
+            margin: 0 auto;
+            background: white;
+            border-radius: 8px;
+            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+            overflow: hidden;
+        }
+        .header {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 30px;
+            text-align: center;
+        }
+        .header h1 {
+            margin: 0;
+            font-size: 2.5em;
+            font-weight: 300;
+        }
+        .header p {
+            margin: 10px 0 0 0;
+            opacity: 0.9;
+            word-break: break-all;
+        }
+        .stats {
+            display: grid;
+            grid-template-columns: repeat(4, 1fr);
+            gap: 20px;
+            padding: 30px;
+            background: #f8f9fa;
+        }
+        .stat-card {
+            background: white;
+            padding: 20px;
+            border-radius: 8px;
+            text-align: center;
+            box-shadow: 0 2px 4px rgba(0,0,0,0.1);
+        }
+        .stat-number {
+            font-size: 2em;
+            font-weight: bold;
+            color: #667eea;
+            margin-bottom: 5px;
+        }
+        .stat-label {
+            color: #666;
+            font-size: 0.9em;
+            text-transform: uppercase;
+            letter-spacing: 1px;
+        }
+        .results {
+            padding: 30px;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+            font-size: 0.85em;
+        }
+        th, td {
+            text-align: left;
+            vertical-align: top;
+            padding: 8px 10px;
+            border-bottom: 1px solid #eee;
+            word-break: break-all;
+        }
+        th {
+            background: #f5f5f5;
+        }
+        td img {
+            display: block;
+            max-width: 80px;
+            max-height: 80px;
+            object-fit: contain;
+            margin-bottom: 4px;
+        }
+        .kind {
+            font-weight: bold;
+            word-break: normal;
+        }
+        .added { color: #2e7d32; }
+        .removed { color: #c62828; }
+        .changed { color: #e65100; }
+        .none {
+            color: #999;
+            font-style: italic;
+        }
+        details li {
+            margin: 2px 0;
+        }
+        .footer {
+            background: #f8f9fa;
+            padding: 20px;
+            text-align: center;
+            color: #666;
+            border-top: 1px solid #e0e0e0;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔀 Logo Crawler Diff</h1>
+            <p>{{.OldPath}}{{if not .OldGeneratedAt.IsZero}} ({{.OldGeneratedAt.Format "2006-01-02 15:04"}}){{end}} → {{.NewPath}}{{if not .NewGeneratedAt.IsZero}} ({{.NewGeneratedAt.Format "2006-01-02 15:04"}}){{end}}</p>
+        </div>
+
+        <div class="stats">
+            <div class="stat-card">
+                <div class="stat-number">{{.Changed}}</div>
+                <div class="stat-label">Changed</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number">{{.Added}}</div>
+                <div class="stat-label">Added</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number">{{.Removed}}</div>
+                <div class="stat-label">Removed</div>
+            </div>
+            <div class="stat-card">
+                <div class="stat-number">{{.Unchanged}}</div>
+                <div class="stat-label">Unchanged</div>
+            </div>
+        </div>
+
+        <div class="results">
+            {{if .Publishers}}
+            <table>
+                <tr><th>Publisher</th><th>Change</th><th>Old best logo</th><th>New best logo</th><th>Logos</th></tr>
+                {{range .Publishers}}
+                <tr>
+                    <td>{{.Name}}{{if ne .Name .Publisher}}<br><small>{{.Publisher}}</small>{{end}}</td>
+                    <td class="kind {{.Kind}}">{{.Kind}}{{if and (eq .Kind "changed") .BestChanged}}<br><small>best logo</small>{{end}}</td>
+                    <td>{{if .OldBest}}<img src="{{logoSrc .OldBest}}" alt="Old best logo" loading="lazy"><a href="{{logoHref .OldBest}}" target="_blank">{{shortURL .OldBest}}</a>{{else}}<span class="none">none</span>{{end}}</td>
+                    <td>{{if .NewBest}}<img src="{{logoSrc .NewBest}}" alt="New best logo" loading="lazy"><a href="{{logoHref .NewBest}}" target="_blank">{{shortURL .NewBest}}</a>{{else}}<span class="none">none</span>{{end}}</td>
+                    <td>
+                        {{if .Gained}}<details><summary class="added">+{{len .Gained}} gained</summary><ul>{{range .Gained}}<li><a href="{{logoHref .}}" target="_blank">{{shortURL .}}</a></li>{{end}}</ul></details>{{end}}
+                        {{if .Lost}}<details><summary class="removed">-{{len .Lost}} lost</summary><ul>{{range .Lost}}<li><a href="{{logoHref .}}" target="_blank">{{shortURL .}}</a></li>{{end}}</ul></details>{{end}}
+                    </td>
+                </tr>
+                {{end}}
+            </table>
+            {{else}}
+            <p class="none">No publisher's logos changed.</p>
+            {{end}}
+        </div>
+
+        <div class="footer">
+            <p>Generated by Logo Crawler on {{.GeneratedAt.Format "2006-01-02 15:04:05"}}</p>
+        </div>
+    </div>
+</body>
+</html>`))
@@ -0,0 +1,39 @@
+package output
+
+import "github.com/Tanmay-Thanvi/logo-crawler/internal/crawler"
+
+// NeedsAttention reports whether a publisher failed or found no logos
+func NeedsAttention(result crawler.PublisherResult) bool {
+	return result.Error != nil || len(result.Logos) == 0
+}
+
+// TrimToBest returns result with Logos cut down to its best logo, if any
+func TrimToBest(result crawler.PublisherResult) crawler.PublisherResult {
+	result.Logos = nil
+	if result.Best != nil {
+		result.Logos = []crawler.LogoInfo{*result.Best}
+	}
+	return result
+}
+
+// BestOnly returns the results with their Logos trimmed to the best logo
+// (see TrimToBest), keeping their order
+func BestOnly(results []crawler.PublisherResult) []crawler.PublisherResult {
+	trimmed := make([]crawler.PublisherResult, 0, len(results))
+	for _, result := range results {
+		trimmed = append(trimmed, TrimToBest(result))
+	}
+	return trimmed
+}
+
+// ErrorsOnly returns the results that need attention (see NeedsAttention),
+// keeping their order
+func ErrorsOnly(results []crawler.PublisherResult) []crawler.PublisherResult {
+	filtered := make([]crawler.PublisherResult, 0, len(results))
+	for _, result := range results {
+		if NeedsAttention(result) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
@@ -1,18 +1,30 @@
 package output
 
 import (
+	"errors"
 	"fmt"
 	"html/template"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/Tanmay-Thanvi/logo-crawler/internal/crawler"
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
 )
 
 // HTMLGenerator handles HTML report generation
 type HTMLGenerator struct {
 	outputPath string
+	// Compact renders only the stats and a publisher to best logo table,
+	// without image grids, to keep reports for large runs small
+	Compact bool
+	// TotalBytes is the number of bytes the run downloaded, shown in the footer
+	TotalBytes int64
+	// ErrorsOnly lists only publishers that failed or found no logos; the
+	// stats still cover every publisher
+	ErrorsOnly bool
 }
 
 // NewHTMLGenerator creates a new HTML generator
@@ -33,6 +45,10 @@ type HTMLReport struct {
 	SuccessRate     float64
 	TotalDuration   time.Duration
 	AvgDuration     time.Duration
+	P50, P90, P99   time.Duration // Per-publisher duration percentiles
+	Compact         bool          // Best logo table instead of image grids
+	TotalBytes      string        // Formatted data downloaded, empty if unknown
+	ErrorsOnly      bool          // Results hold only publishers needing attention
 	Results         []crawler.PublisherResult
 }
 
@@ -50,8 +66,17 @@ func (hg *HTMLGenerator) GenerateReport(results []crawler.PublisherResult, total
 		SuccessRate:     stats.SuccessRate,
 		TotalDuration:   totalDuration,
 		AvgDuration:     totalDuration / time.Duration(stats.TotalPublishers),
+		P50:             stats.P50,
+		P90:             stats.P90,
+		P99:             stats.P99,
+		Compact:         hg.Compact,
+		TotalBytes:      hg.formatBytes(),
+		ErrorsOnly:      hg.ErrorsOnly,
 		Results:         results,
 	}
+	if hg.ErrorsOnly {
+		report.Results = ErrorsOnly(results)
+	}
 
 	tmpl := hg.getHTMLTemplate()
 
@@ -74,6 +99,36 @@ func (hg *HTMLGenerator) GenerateReport(results []crawler.PublisherResult, total
 	return nil
 }
 
+// ReportFilePattern matches the timestamped HTML reports written by default
+const ReportFilePattern = "logo-crawler-report-*.html"
+
+// PruneReports deletes all but the keep most recent reports matching
+// ReportFilePattern in dir and returns how many were deleted. Other files
+// are never touched.
+func PruneReports(dir string, keep int) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, ReportFilePattern))
+	if err != nil {
+		return 0, err
+	}
+	if len(paths) <= keep {
+		return 0, nil
+	}
+
+	// The timestamp in the name sorts chronologically
+	sort.Strings(paths)
+
+	deleted := 0
+	var errs []error
+	for _, path := range paths[:len(paths)-keep] {
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted, errors.Join(errs...)
+}
+
 // Stats holds processing statistics
 type Stats struct {
 	TotalPublishers int
@@ -81,13 +136,18 @@ type Stats struct {
 	ErrorCount      int
 	TotalLogos      int
 	SuccessRate     float64
+	P50, P90, P99   time.Duration
 }
 
 // calculateStats calculates processing statistics
 func (hg *HTMLGenerator) calculateStats(results []crawler.PublisherResult) Stats {
 	stats := Stats{TotalPublishers: len(results)}
 
+	durations := make([]time.Duration, 0, len(results))
 	for _, result := range results {
+		if !result.Skipped {
+			durations = append(durations, result.Duration)
+		}
 		if result.Error != nil {
 			stats.ErrorCount++
 			continue
@@ -100,6 +160,8 @@ func (hg *HTMLGenerator) calculateStats(results []crawler.PublisherResult) Stats
 	}
 
 	stats.SuccessRate = float64(stats.ValidPublishers) / float64(stats.TotalPublishers) * 100
+	p := utils.Percentiles(durations, 50, 90, 99)
+	stats.P50, stats.P90, stats.P99 = p[0], p[1], p[2]
 	return stats
 }
 
@@ -203,6 +265,39 @@ func (hg *HTMLGenerator) getHTMLTemplate() *template.Template {
             color: #d32f2f;
             padding: 15px 20px;
         }
+        .compact {
+            width: 100%;
+            border-collapse: collapse;
+            font-size: 0.85em;
+        }
+        .compact th, .compact td {
+            text-align: left;
+            padding: 6px 10px;
+            border-bottom: 1px solid #eee;
+            word-break: break-all;
+        }
+        .compact th {
+            background: #f5f5f5;
+        }
+        .compact-error {
+            color: #c62828;
+        }
+        .rejected {
+            padding: 10px 20px 15px;
+            font-size: 0.85em;
+            color: #555;
+        }
+        .rejected summary {
+            cursor: pointer;
+            color: #666;
+        }
+        .rejected li {
+            word-break: break-all;
+            margin: 4px 0;
+        }
+        .rejected-reason {
+            color: #d32f2f;
+        }
         .logos {
             padding: 20px;
             display: grid;
@@ -274,6 +369,32 @@ func (hg *HTMLGenerator) getHTMLTemplate() *template.Template {
             color: #666;
             margin-bottom: 8px;
         }
+        .logo-alt {
+            font-size: 0.75em;
+            color: #444;
+            font-style: italic;
+            margin-bottom: 8px;
+        }
+        .logo-score {
+            font-size: 0.75em;
+            color: #667eea;
+            font-weight: bold;
+            margin-bottom: 8px;
+        }
+        .logo-warning {
+            font-size: 0.75em;
+            color: #e65100;
+            margin-bottom: 8px;
+        }
+        .rank-badge {
+            background: #667eea;
+            color: white;
+            padding: 3px 8px;
+            border-radius: 12px;
+            font-size: 0.7em;
+            font-weight: bold;
+            display: inline-block;
+        }
         .best-badge {
             background: #4caf50;
             color: white;
@@ -366,28 +487,53 @@ func (hg *HTMLGenerator) getHTMLTemplate() *template.Template {
                 <div class="stat-number">{{printf "%.3f" .TotalDuration.Seconds}}s</div>
                 <div class="stat-label">Total Time</div>
             </div>
+            <div class="stat-card">
+                <div class="stat-number">{{printf "%.2f" .P50.Seconds}}s / {{printf "%.2f" .P90.Seconds}}s / {{printf "%.2f" .P99.Seconds}}s</div>
+                <div class="stat-label">p50 / p90 / p99</div>
+            </div>
         </div>
         
         <div class="results">
             <h2>📊 Results</h2>
+            {{if .ErrorsOnly}}<p>Showing only the {{len .Results}} publishers with errors or no logos.</p>{{end}}
+            {{if .Compact}}
+            <table class="compact">
+                <tr><th>Publisher</th><th>Best logo</th><th>Size</th><th>Duration</th></tr>
+                {{range .Results}}
+                <tr>
+                    <td>{{.Name}}</td>
+                    {{if .Error}}
+                    <td colspan="2" class="compact-error">❌ {{.Error}}</td>
+                    {{else if .Best}}
+                    <td><a href="{{logoHref .Best.URL}}" target="_blank">{{shortURL .Best.URL}}</a></td>
+                    <td>{{.Best.Width}}x{{.Best.Height}}</td>
+                    {{else}}
+                    <td colspan="2" class="compact-error">No valid logos found</td>
+                    {{end}}
+                    <td>{{.Duration}}</td>
+                </tr>
+                {{end}}
+            </table>
+            {{else}}
             {{range .Results}}
             <div class="publisher">
                 {{if .Error}}
                 <div class="publisher-error">
-                    <strong>❌ {{.Publisher}}</strong> ({{.Duration}}) - ERROR: {{.Error}}
+                    <strong>❌ {{.Name}}</strong> ({{.Duration}}) - ERROR: {{.Error}}
                 </div>
                 {{else}}
                 <div class="publisher-header">
-                    <div class="publisher-name">🔎 {{.Publisher}}</div>
-                    <div class="publisher-duration">Processed in {{.Duration}}</div>
+                    <div class="publisher-name">🔎 {{.Name}}{{if .DisplayName}} ({{.Publisher}}){{end}}{{if .ResolvedDomain}} → {{.ResolvedDomain}}{{end}}</div>
+                    <div class="publisher-duration">Processed in {{.Duration}} (extract {{.ExtractDuration}}, validate {{.ValidateDuration}})</div>
                 </div>
                 <div class="logos">
                     {{if .Logos}}
-                        {{$bestURL := .Best.URL}}
+                        {{$bestURL := ""}}{{with .Best}}{{$bestURL = .URL}}{{end}}
+                        {{$top := .TopN}}
                         {{range .Logos}}
                         <div class="logo-card {{if eq .URL $bestURL}}best{{end}}">
                             <div class="logo-image-container">
-                                <img src="{{.URL}}" alt="Logo" class="logo-image" 
+                                <img src="{{logoSrc .URL}}" alt="{{or .Alt "Logo"}}" class="logo-image" 
                                      onerror="this.classList.add('error'); this.nextElementSibling.classList.add('show');"
                                      onload="this.classList.remove('loading'); this.nextElementSibling.classList.remove('show');"
                                      onloadstart="this.classList.add('loading');">
@@ -397,11 +543,19 @@ func (hg *HTMLGenerator) getHTMLTemplate() *template.Template {
                                 </div>
                             </div>
                             <div class="logo-info">
-                                <a href="{{.URL}}" target="_blank" class="logo-url">{{.URL}}</a>
-                                <div class="logo-dimensions">{{.Width}}x{{.Height}} pixels</div>
+                                <a href="{{logoHref .URL}}" target="_blank" class="logo-url">{{shortURL .URL}}</a>
+                                {{if .Alt}}<div class="logo-alt">“{{.Alt}}”</div>{{end}}
+                                <div class="logo-dimensions">{{.Width}}x{{.Height}} pixels · ratio {{printf "%.2f" .AspectRatio}}{{if .HasAlpha}} · transparent{{end}}</div>
+                                <div class="logo-score">Score: {{.Score}}{{if .Source}} · found via {{.Source}}{{end}}</div>
+                                {{range .Warnings}}
+                                <div class="logo-warning">⚠️ {{.}}</div>
+                                {{end}}
                                 {{if eq .URL $bestURL}}
                                 <span class="best-badge">✅ SUGGESTED</span>
                                 {{end}}
+                                {{if gt (len $top) 1}}{{with rank $top .URL}}
+                                <span class="rank-badge">#{{.}}</span>
+                                {{end}}{{end}}
                             </div>
                         </div>
                         {{end}}
@@ -410,17 +564,83 @@ func (hg *HTMLGenerator) getHTMLTemplate() *template.Template {
                     {{end}}
                 </div>
                 {{end}}
+                {{if and .Rejected (not .Best)}}
+                <details class="rejected">
+                    <summary>{{len .Rejected}} rejected candidates</summary>
+                    <ul>
+                        {{range .Rejected}}
+                        <li>[{{.Source}}] <a href="{{logoHref .URL}}" target="_blank">{{shortURL .URL}}</a> - <span class="rejected-reason">{{.Reason}}</span></li>
+                        {{end}}
+                    </ul>
+                </details>
+                {{end}}
             </div>
             {{end}}
+            {{end}}
         </div>
         
         <div class="footer">
             <p>Generated by Logo Crawler - Enterprise Edition</p>
+            {{if .TotalBytes}}<p>Data downloaded: {{.TotalBytes}}</p>{{end}}
             <p><small>Note: Some images may not display due to CORS restrictions, but all links are clickable to view the logos directly.</small></p>
         </div>
     </div>
 </body>
 </html>`
 
-	return template.Must(template.New("report").Parse(tmpl))
+	return template.Must(template.New("report").Funcs(template.FuncMap{"rank": rank, "logoSrc": logoSrc, "logoHref": logoHref, "shortURL": utils.ShortURL}).Parse(tmpl))
+}
+
+// formatBytes formats TotalBytes for the footer, empty when nothing was counted
+func (hg *HTMLGenerator) formatBytes() string {
+	if hg.TotalBytes <= 0 {
+		return ""
+	}
+	return utils.FormatBytes(hg.TotalBytes)
+}
+
+// logoSrc marks inline data:image/ URIs as safe so html/template keeps them
+// in img src attributes, where even SVG cannot run scripts; other URLs are
+// sanitized as usual
+func logoSrc(url string) any {
+	if utils.IsDataURI(url) && strings.HasPrefix(strings.ToLower(url), "data:image/") {
+		return template.URL(url)
+	}
+	return url
+}
+
+// rasterDataTypes are the data URI media types safe to open as a page
+var rasterDataTypes = map[string]bool{
+	"image/png":                true,
+	"image/jpeg":               true,
+	"image/gif":                true,
+	"image/webp":               true,
+	"image/x-icon":             true,
+	"image/vnd.microsoft.icon": true,
+}
+
+// logoHref is logoSrc for link href attributes. Opening an SVG data URI
+// runs any script it holds, so only raster data URIs are trusted there.
+func logoHref(url string) any {
+	if !utils.IsDataURI(url) {
+		return url
+	}
+	mediaType := strings.TrimPrefix(strings.ToLower(url), "data:")
+	if end := strings.IndexAny(mediaType, ";,"); end >= 0 {
+		mediaType = mediaType[:end]
+	}
+	if rasterDataTypes[strings.TrimSpace(mediaType)] {
+		return template.URL(url)
+	}
+	return url
+}
+
+// rank returns the 1-based position of url among the top logos, 0 if absent
+func rank(top []crawler.LogoInfo, url string) int {
+	for i, logo := range top {
+		if logo.URL == url {
+			return i + 1
+		}
+	}
+	return 0
 }
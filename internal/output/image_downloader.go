@@ -0,0 +1,143 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/crawler"
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/utils"
+)
+
+// imageExtensions maps image content types to file extensions
+var imageExtensions = map[string]string{
+	"image/png":                ".png",
+	"image/jpeg":               ".jpg",
+	"image/gif":                ".gif",
+	"image/webp":               ".webp",
+	"image/svg+xml":            ".svg",
+	"image/x-icon":             ".ico",
+	"image/vnd.microsoft.icon": ".ico",
+}
+
+// ImageDownloader saves each publisher's best logo to disk
+type ImageDownloader struct {
+	outputDir    string
+	client       utils.Doer
+	maxReadBytes int64
+}
+
+// NewImageDownloader creates a new image downloader writing into outputDir.
+// client should be the crawl's run client so downloads share its timeout,
+// request budget and pacing; maxReadBytes caps each body, 0 for no limit.
+func NewImageDownloader(outputDir string, client utils.Doer, maxReadBytes int64) *ImageDownloader {
+	return &ImageDownloader{
+		outputDir:    outputDir,
+		client:       client,
+		maxReadBytes: maxReadBytes,
+	}
+}
+
+// Download fetches the best logo of each publisher and writes it to
+// <dir>/<publisher>.<ext>. Publishers without a best logo are skipped; a
+// failed download does not stop the others and all failures are returned.
+func (id *ImageDownloader) Download(results []crawler.PublisherResult) error {
+	if err := os.MkdirAll(id.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	var errs []error
+	for _, result := range results {
+		if result.Best == nil {
+			continue
+		}
+		if err := id.downloadLogo(result.Publisher, result.Best.URL); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Publisher, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// downloadLogo fetches a single logo and writes it to disk
+func (id *ImageDownloader) downloadLogo(publisher, logoURL string) error {
+	if utils.IsDataURI(logoURL) {
+		mediaType, data, err := utils.DecodeDataURI(logoURL)
+		if err != nil {
+			return err
+		}
+		name := SafeFileName(publisher) + imageExtension(mediaType, "")
+		if err := os.WriteFile(filepath.Join(id.outputDir, name), data, 0644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		return nil
+	}
+
+	req, err := utils.NewRequest(http.MethodGet, logoURL)
+	if err != nil {
+		return err
+	}
+
+	resp, err := id.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var body io.Reader = resp.Body
+	if id.maxReadBytes > 0 {
+		body = utils.NewLimitedReader(resp.Body, id.maxReadBytes)
+	}
+
+	name := SafeFileName(publisher) + imageExtension(resp.Header.Get("Content-Type"), logoURL)
+	path := filepath.Join(id.outputDir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		os.Remove(path) // Never leave a partial image behind
+		if errors.Is(err, utils.ErrReadLimitExceeded) {
+			return fmt.Errorf("over %d bytes read", id.maxReadBytes)
+		}
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// imageExtension infers a file extension from the Content-Type, falling
+// back to the URL path and finally to ".img"
+func imageExtension(contentType, logoURL string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if ext, ok := imageExtensions[strings.ToLower(mediaType)]; ok {
+			return ext
+		}
+	}
+
+	if u, err := url.Parse(logoURL); err == nil {
+		ext := strings.ToLower(path.Ext(u.Path))
+		for _, known := range imageExtensions {
+			if ext == known {
+				return ext
+			}
+		}
+		if ext == ".jpeg" {
+			return ".jpg"
+		}
+	}
+
+	return ".img"
+}
@@ -0,0 +1,281 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/crawler"
+)
+
+// JSONLogo is the JSON representation of a validated logo
+type JSONLogo struct {
+	URL            string   `json:"url"`
+	Width          int      `json:"width"`
+	Height         int      `json:"height"`
+	Format         string   `json:"format,omitempty"`
+	AspectRatio    float64  `json:"aspect_ratio"`
+	HasAlpha       bool     `json:"has_alpha"`
+	Background     string   `json:"background,omitempty"`
+	Score          int      `json:"score"`
+	Source         string   `json:"source,omitempty"`
+	DeclaredWidth  int      `json:"declared_width,omitempty"`
+	DeclaredHeight int      `json:"declared_height,omitempty"`
+	Alt            string   `json:"alt,omitempty"`
+	Warnings       []string `json:"warnings,omitempty"`
+}
+
+// JSONRejected is the JSON representation of a rejected candidate
+type JSONRejected struct {
+	URL    string `json:"url"`
+	Source string `json:"source,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// JSONResult is the JSON representation of a publisher result
+type JSONResult struct {
+	Publisher  string         `json:"publisher"`
+	Name       string         `json:"display_name"`
+	Resolved   string         `json:"resolved_domain,omitempty"`
+	Best       *JSONLogo      `json:"best,omitempty"`
+	TopN       []JSONLogo     `json:"top_n,omitempty"`
+	Logos      []JSONLogo     `json:"logos"`
+	Rejected   []JSONRejected `json:"rejected,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	Skipped    bool           `json:"skipped,omitempty"`
+	DurationMs int64          `json:"duration_ms"`
+	ExtractMs  int64          `json:"extract_duration_ms"`
+	ValidateMs int64          `json:"validate_duration_ms"`
+}
+
+// JSONReport is the JSON representation of a whole run
+type JSONReport struct {
+	GeneratedAt     time.Time    `json:"generated_at"`
+	TotalDurationMs int64        `json:"total_duration_ms"`
+	Results         []JSONResult `json:"results"`
+}
+
+// NewJSONResult converts a publisher result into its JSON representation
+func NewJSONResult(result crawler.PublisherResult) JSONResult {
+	jr := JSONResult{
+		Publisher:  result.Publisher,
+		Name:       result.Name(),
+		Resolved:   result.ResolvedDomain,
+		Logos:      make([]JSONLogo, 0, len(result.Logos)),
+		Skipped:    result.Skipped,
+		DurationMs: result.Duration.Milliseconds(),
+		ExtractMs:  result.ExtractDuration.Milliseconds(),
+		ValidateMs: result.ValidateDuration.Milliseconds(),
+	}
+	if result.Error != nil {
+		jr.Error = result.Error.Error()
+	}
+	if result.Best != nil {
+		best := newJSONLogo(*result.Best)
+		jr.Best = &best
+	}
+	for _, logo := range result.TopN {
+		jr.TopN = append(jr.TopN, newJSONLogo(logo))
+	}
+	for _, logo := range result.Logos {
+		jr.Logos = append(jr.Logos, newJSONLogo(logo))
+	}
+	for _, rejected := range result.Rejected {
+		jr.Rejected = append(jr.Rejected, JSONRejected{
+			URL:    rejected.URL,
+			Source: rejected.Source,
+			Reason: rejected.Reason,
+		})
+	}
+	return jr
+}
+
+// newJSONLogo converts a logo into its JSON representation
+func newJSONLogo(logo crawler.LogoInfo) JSONLogo {
+	return JSONLogo{
+		URL:            logo.URL,
+		Width:          logo.Width,
+		Height:         logo.Height,
+		Format:         logo.Format,
+		AspectRatio:    logo.AspectRatio,
+		HasAlpha:       logo.HasAlpha,
+		Background:     logo.Background,
+		Score:          logo.Score,
+		Source:         logo.Source,
+		DeclaredWidth:  logo.DeclaredWidth,
+		DeclaredHeight: logo.DeclaredHeight,
+		Alt:            logo.Alt,
+		Warnings:       logo.Warnings,
+	}
+}
+
+// PublisherResult converts a JSON result back into a publisher result.
+// Fields the JSON form leaves out, such as content hashes and Index, stay
+// zero; errors come back as plain messages.
+func (jr JSONResult) PublisherResult() crawler.PublisherResult {
+	result := crawler.PublisherResult{
+		Publisher:        jr.Publisher,
+		ResolvedDomain:   jr.Resolved,
+		Skipped:          jr.Skipped,
+		Duration:         time.Duration(jr.DurationMs) * time.Millisecond,
+		ExtractDuration:  time.Duration(jr.ExtractMs) * time.Millisecond,
+		ValidateDuration: time.Duration(jr.ValidateMs) * time.Millisecond,
+	}
+	if jr.Name != jr.Publisher {
+		result.DisplayName = jr.Name
+	}
+	if jr.Error != "" {
+		result.Error = errors.New(jr.Error)
+	}
+	if jr.Best != nil {
+		best := jr.Best.LogoInfo()
+		result.Best = &best
+	}
+	for _, logo := range jr.TopN {
+		result.TopN = append(result.TopN, logo.LogoInfo())
+	}
+	for _, logo := range jr.Logos {
+		result.Logos = append(result.Logos, logo.LogoInfo())
+	}
+	for _, rejected := range jr.Rejected {
+		result.Rejected = append(result.Rejected, crawler.RejectedCandidate{
+			URL:    rejected.URL,
+			Source: rejected.Source,
+			Reason: rejected.Reason,
+		})
+	}
+	return result
+}
+
+// LogoInfo converts a JSON logo back into a validated logo
+func (jl JSONLogo) LogoInfo() crawler.LogoInfo {
+	return crawler.LogoInfo{
+		URL:            jl.URL,
+		Width:          jl.Width,
+		Height:         jl.Height,
+		Format:         jl.Format,
+		Valid:          true,
+		AspectRatio:    jl.AspectRatio,
+		HasAlpha:       jl.HasAlpha,
+		Background:     jl.Background,
+		Score:          jl.Score,
+		Source:         jl.Source,
+		DeclaredWidth:  jl.DeclaredWidth,
+		DeclaredHeight: jl.DeclaredHeight,
+		Alt:            jl.Alt,
+		Warnings:       jl.Warnings,
+	}
+}
+
+// writeJSONFile writes v as indented JSON to path, creating parent directories
+func writeJSONFile(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+	return nil
+}
+
+// JSONGenerator writes all results to a single JSON file
+type JSONGenerator struct {
+	outputPath string
+	// ErrorsOnly writes only publishers that failed or found no logos
+	ErrorsOnly bool
+}
+
+// NewJSONGenerator creates a new JSON generator
+func NewJSONGenerator(outputPath string) *JSONGenerator {
+	return &JSONGenerator{
+		outputPath: outputPath,
+	}
+}
+
+// GenerateReport writes the results as one JSON document
+func (jg *JSONGenerator) GenerateReport(results []crawler.PublisherResult, totalDuration time.Duration) error {
+	if jg.ErrorsOnly {
+		results = ErrorsOnly(results)
+	}
+	report := JSONReport{
+		GeneratedAt:     time.Now(),
+		TotalDurationMs: totalDuration.Milliseconds(),
+		Results:         make([]JSONResult, 0, len(results)),
+	}
+	for _, result := range results {
+		report.Results = append(report.Results, NewJSONResult(result))
+	}
+
+	return writeJSONFile(jg.outputPath, report)
+}
+
+// JSONDirGenerator writes one JSON file per publisher into a directory
+type JSONDirGenerator struct {
+	outputDir string
+}
+
+// NewJSONDirGenerator creates a new per-publisher JSON generator
+func NewJSONDirGenerator(outputDir string) *JSONDirGenerator {
+	return &JSONDirGenerator{
+		outputDir: outputDir,
+	}
+}
+
+// GenerateFiles writes <dir>/<domain>.json for each publisher, naming it
+// after the publisher's detected domain without "www.". Publishers sharing a
+// name, such as example.com and https://www.example.com, get -2, -3, ...
+// suffixes in input order. It keeps going when a single file fails and
+// returns the number of files written along with the joined per-file errors.
+func (jd *JSONDirGenerator) GenerateFiles(results []crawler.PublisherResult) (int, error) {
+	written := 0
+	var errs []error
+
+	processor := crawler.NewDomainProcessor()
+	used := make(map[string]bool, len(results))
+	for _, result := range results {
+		base := SafeFileName(strings.TrimPrefix(processor.DetectDomain(result.Publisher), "www."))
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[name] = true
+
+		path := filepath.Join(jd.outputDir, name+".json")
+		if err := writeJSONFile(path, NewJSONResult(result)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Publisher, err))
+			continue
+		}
+		written++
+	}
+
+	return written, errors.Join(errs...)
+}
+
+// SafeFileName turns a publisher string into a safe file name, replacing
+// anything other than letters, digits, dots and dashes with underscores
+func SafeFileName(publisher string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.ToLower(strings.TrimSpace(publisher)))
+
+	name = strings.Trim(name, "._")
+	if name == "" {
+		return "publisher"
+	}
+	return name
+}
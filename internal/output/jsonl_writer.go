@@ -0,0 +1,30 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/crawler"
+)
+
+// JSONLWriter streams results as JSON lines, one object per publisher, as
+// they complete
+type JSONLWriter struct {
+	encoder *json.Encoder
+}
+
+// NewJSONLWriter creates a JSON lines writer on w, e.g. a file or os.Stdout
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	return &JSONLWriter{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Write encodes result as a single line
+func (jw *JSONLWriter) Write(result crawler.PublisherResult) error {
+	if err := jw.encoder.Encode(NewJSONResult(result)); err != nil {
+		return fmt.Errorf("failed to write JSON line: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package output
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/crawler"
+)
+
+// durationBuckets are the upper bounds, in seconds, of the per-publisher
+// duration histogram
+var durationBuckets = []float64{0.25, 0.5, 1, 2.5, 5, 10, 20, 45}
+
+// publisherOutcomes are the outcome label values of the publishers counter
+var publisherOutcomes = []string{"success", "no_logos", "error", "skipped"}
+
+// Metrics accumulates run metrics from publisher results and serves them in
+// the Prometheus text exposition format. It is safe for concurrent use.
+type Metrics struct {
+	mu                 sync.Mutex
+	publishers         map[string]int64 // By outcome
+	logos              int64
+	validationFailures int64
+	bucketCounts       []int64 // Non-cumulative, one per durationBuckets entry plus +Inf
+	durationSum        float64
+	durationCount      int64
+}
+
+// NewMetrics creates empty metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		publishers:   make(map[string]int64),
+		bucketCounts: make([]int64, len(durationBuckets)+1),
+	}
+}
+
+// Observe records one finished publisher; a nil Metrics ignores it
+func (m *Metrics) Observe(result crawler.PublisherResult) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.publishers[outcome(result)]++
+	m.logos += int64(len(result.Logos))
+	m.validationFailures += int64(len(result.Rejected))
+	if result.Skipped {
+		return
+	}
+
+	seconds := result.Duration.Seconds()
+	bucket := len(durationBuckets)
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			bucket = i
+			break
+		}
+	}
+	m.bucketCounts[bucket]++
+	m.durationSum += seconds
+	m.durationCount++
+}
+
+// outcome labels a result for the publishers counter
+func outcome(result crawler.PublisherResult) string {
+	switch {
+	case result.Skipped:
+		return "skipped"
+	case result.Error != nil:
+		return "error"
+	case len(result.Logos) == 0:
+		return "no_logos"
+	default:
+		return "success"
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	fmt.Fprintln(w, "# HELP logo_crawler_publishers_processed_total Publishers processed, by outcome.")
+	fmt.Fprintln(w, "# TYPE logo_crawler_publishers_processed_total counter")
+	for _, label := range publisherOutcomes {
+		fmt.Fprintf(w, "logo_crawler_publishers_processed_total{outcome=%q} %d\n", label, m.publishers[label])
+	}
+
+	fmt.Fprintln(w, "# HELP logo_crawler_logos_found_total Valid logos found.")
+	fmt.Fprintln(w, "# TYPE logo_crawler_logos_found_total counter")
+	fmt.Fprintf(w, "logo_crawler_logos_found_total %d\n", m.logos)
+
+	fmt.Fprintln(w, "# HELP logo_crawler_validation_failures_total Candidates rejected during validation.")
+	fmt.Fprintln(w, "# TYPE logo_crawler_validation_failures_total counter")
+	fmt.Fprintf(w, "logo_crawler_validation_failures_total %d\n", m.validationFailures)
+
+	fmt.Fprintln(w, "# HELP logo_crawler_publisher_duration_seconds Time spent on each publisher, skipped publishers excluded.")
+	fmt.Fprintln(w, "# TYPE logo_crawler_publisher_duration_seconds histogram")
+	var cumulative int64
+	for i, bound := range durationBuckets {
+		cumulative += m.bucketCounts[i]
+		fmt.Fprintf(w, "logo_crawler_publisher_duration_seconds_bucket{le=%q} %d\n", strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+	}
+	fmt.Fprintf(w, "logo_crawler_publisher_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.durationCount)
+	fmt.Fprintf(w, "logo_crawler_publisher_duration_seconds_sum %g\n", m.durationSum)
+	fmt.Fprintf(w, "logo_crawler_publisher_duration_seconds_count %d\n", m.durationCount)
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/crawler"
+)
+
+// Report orderings accepted by SortResults. The non-default orderings put
+// the publishers most worth reviewing first.
+const (
+	SortInput    = "input"    // Publisher file order
+	SortDuration = "duration" // Slowest first
+	SortSuccess  = "success"  // Errors, then publishers without logos, then the rest
+	SortScore    = "score"    // Lowest best-logo score first, publishers without one leading
+)
+
+// SortOrders lists the valid report orderings
+var SortOrders = []string{SortInput, SortDuration, SortSuccess, SortScore}
+
+// SortResults returns a copy of results in the given order; an empty order
+// means SortInput. Ties keep input order.
+func SortResults(results []crawler.PublisherResult, order string) []crawler.PublisherResult {
+	sorted := slices.Clone(results)
+	byIndex := func(a, b crawler.PublisherResult) int { return cmp.Compare(a.Index, b.Index) }
+
+	switch order {
+	case SortDuration:
+		slices.SortStableFunc(sorted, func(a, b crawler.PublisherResult) int {
+			return cmp.Or(cmp.Compare(b.Duration, a.Duration), byIndex(a, b))
+		})
+	case SortSuccess:
+		slices.SortStableFunc(sorted, func(a, b crawler.PublisherResult) int {
+			return cmp.Or(cmp.Compare(successRank(a), successRank(b)), byIndex(a, b))
+		})
+	case SortScore:
+		slices.SortStableFunc(sorted, func(a, b crawler.PublisherResult) int {
+			return cmp.Or(cmp.Compare(bestScore(a), bestScore(b)), byIndex(a, b))
+		})
+	default:
+		slices.SortStableFunc(sorted, byIndex)
+	}
+	return sorted
+}
+
+// successRank orders failed publishers before those without logos, and
+// those before publishers with logos
+func successRank(result crawler.PublisherResult) int {
+	switch {
+	case result.Error != nil:
+		return 0
+	case len(result.Logos) == 0:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// bestScore returns the best logo's score, or the lowest int when there is
+// no best logo
+func bestScore(result crawler.PublisherResult) int {
+	if result.Best == nil {
+		return math.MinInt
+	}
+	return result.Best.Score
+}
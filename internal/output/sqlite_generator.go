@@ -0,0 +1,132 @@
+package output
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/crawler"
+	_ "modernc.org/sqlite" // Pure Go driver, no cgo needed
+)
+
+// sqliteSchema creates the tables on first use; later runs append to them
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at        TIMESTAMP NOT NULL,
+	total_duration_ms INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS publishers (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id          INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+	publisher       TEXT NOT NULL,
+	resolved_domain TEXT,
+	duration_ms     INTEGER NOT NULL,
+	error           TEXT,
+	skipped         BOOLEAN NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS logos (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	publisher_id INTEGER NOT NULL REFERENCES publishers(id) ON DELETE CASCADE,
+	url          TEXT NOT NULL,
+	width        INTEGER NOT NULL,
+	height       INTEGER NOT NULL,
+	format       TEXT,
+	source       TEXT,
+	score        INTEGER NOT NULL,
+	is_best      BOOLEAN NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_publishers_run ON publishers(run_id);
+CREATE INDEX IF NOT EXISTS idx_publishers_publisher ON publishers(publisher);
+CREATE INDEX IF NOT EXISTS idx_logos_publisher ON logos(publisher_id);
+`
+
+// SQLiteGenerator appends each run's results to a SQLite database so crawl
+// history can be queried with SQL
+type SQLiteGenerator struct {
+	dbPath string
+}
+
+// NewSQLiteGenerator creates a new SQLite generator
+func NewSQLiteGenerator(dbPath string) *SQLiteGenerator {
+	return &SQLiteGenerator{
+		dbPath: dbPath,
+	}
+}
+
+// GenerateReport inserts one run row, a publisher row per result and a logo
+// row per valid logo, all in a single transaction
+func (sg *SQLiteGenerator) GenerateReport(results []crawler.PublisherResult, totalDuration time.Duration) error {
+	if err := os.MkdirAll(filepath.Dir(sg.dbPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", sg.dbPath+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	run, err := tx.Exec(`INSERT INTO runs (started_at, total_duration_ms) VALUES (?, ?)`,
+		time.Now().Add(-totalDuration).UTC(), totalDuration.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to insert run: %w", err)
+	}
+	runID, err := run.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read run id: %w", err)
+	}
+
+	for _, result := range results {
+		if err := sg.insertPublisher(tx, runID, result); err != nil {
+			return fmt.Errorf("%s: %w", result.Publisher, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit SQLite report: %w", err)
+	}
+	return nil
+}
+
+// insertPublisher inserts result and its logos under runID
+func (sg *SQLiteGenerator) insertPublisher(tx *sql.Tx, runID int64, result crawler.PublisherResult) error {
+	var errText sql.NullString
+	if result.Error != nil {
+		errText = sql.NullString{String: result.Error.Error(), Valid: true}
+	}
+	resolved := sql.NullString{String: result.ResolvedDomain, Valid: result.ResolvedDomain != ""}
+
+	publisher, err := tx.Exec(`INSERT INTO publishers (run_id, publisher, resolved_domain, duration_ms, error, skipped)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		runID, result.Publisher, resolved, result.Duration.Milliseconds(), errText, result.Skipped)
+	if err != nil {
+		return fmt.Errorf("failed to insert publisher: %w", err)
+	}
+	publisherID, err := publisher.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read publisher id: %w", err)
+	}
+
+	for _, logo := range result.Logos {
+		isBest := result.Best != nil && logo.URL == result.Best.URL
+		if _, err := tx.Exec(`INSERT INTO logos (publisher_id, url, width, height, format, source, score, is_best)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			publisherID, logo.URL, logo.Width, logo.Height, logo.Format, logo.Source, logo.Score, isBest); err != nil {
+			return fmt.Errorf("failed to insert logo: %w", err)
+		}
+	}
+	return nil
+}
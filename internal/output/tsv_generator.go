@@ -0,0 +1,56 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Tanmay-Thanvi/logo-crawler/internal/crawler"
+)
+
+// tsvReplacer keeps error messages on one line and within their column
+var tsvReplacer = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+// TSVGenerator writes the CSV report's columns tab-separated and unquoted,
+// for awk and cut pipelines. URLs and domains are assumed free of tabs.
+type TSVGenerator struct {
+	outputPath string
+}
+
+// NewTSVGenerator creates a new TSV generator
+func NewTSVGenerator(outputPath string) *TSVGenerator {
+	return &TSVGenerator{
+		outputPath: outputPath,
+	}
+}
+
+// GenerateReport writes the results as TSV, starting with a header row
+func (tg *TSVGenerator) GenerateReport(results []crawler.PublisherResult) error {
+	dir := filepath.Dir(tg.outputPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	file, err := os.Create(tg.outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create TSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	fmt.Fprintln(writer, strings.Join(csvHeader, "\t"))
+	for _, result := range results {
+		row := csvRow(result)
+		for i, field := range row {
+			row[i] = tsvReplacer.Replace(field)
+		}
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write TSV file: %w", err)
+	}
+	return nil
+}
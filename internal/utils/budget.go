@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// ErrRequestBudgetExhausted is returned for requests made after the budget is spent
+var ErrRequestBudgetExhausted = errors.New("request budget exhausted")
+
+// RequestBudget caps the total number of outbound requests made during a run.
+// It is safe for concurrent use; a limit of 0 or less means unlimited.
+type RequestBudget struct {
+	limit int64
+	used  atomic.Int64
+}
+
+// NewRequestBudget creates a request budget with the given limit
+func NewRequestBudget(limit int) *RequestBudget {
+	return &RequestBudget{limit: int64(limit)}
+}
+
+// Take reserves one request, returning false once the budget is exhausted
+func (rb *RequestBudget) Take() bool {
+	if rb.limit <= 0 {
+		rb.used.Add(1)
+		return true
+	}
+
+	for {
+		used := rb.used.Load()
+		if used >= rb.limit {
+			return false
+		}
+		if rb.used.CompareAndSwap(used, used+1) {
+			return true
+		}
+	}
+}
+
+// Exhausted reports whether no requests remain in the budget
+func (rb *RequestBudget) Exhausted() bool {
+	return rb.limit > 0 && rb.used.Load() >= rb.limit
+}
+
+// Used returns the number of requests made so far
+func (rb *RequestBudget) Used() int64 {
+	return rb.used.Load()
+}
+
+// Limit returns the configured limit (0 or less means unlimited)
+func (rb *RequestBudget) Limit() int64 {
+	return rb.limit
+}
+
+// WrapClient returns a copy of client whose requests draw from the budget
+func (rb *RequestBudget) WrapClient(client *http.Client) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	wrapped := *client
+	wrapped.Transport = &budgetTransport{base: base, budget: rb}
+	return &wrapped
+}
+
+// budgetTransport rejects requests once the budget is exhausted
+type budgetTransport struct {
+	base   http.RoundTripper
+	budget *RequestBudget
+}
+
+// RoundTrip implements http.RoundTripper
+func (bt *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !bt.budget.Take() {
+		return nil, ErrRequestBudgetExhausted
+	}
+	return bt.base.RoundTrip(req)
+}
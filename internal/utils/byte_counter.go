@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// ByteCounter accumulates the number of response body bytes read during a
+// run. It is safe for concurrent use.
+type ByteCounter struct {
+	total atomic.Int64
+}
+
+// NewByteCounter creates an empty byte counter
+func NewByteCounter() *ByteCounter {
+	return &ByteCounter{}
+}
+
+// Add records n bytes
+func (bc *ByteCounter) Add(n int64) {
+	bc.total.Add(n)
+}
+
+// Total returns the number of bytes recorded so far; a nil counter reports 0
+func (bc *ByteCounter) Total() int64 {
+	if bc == nil {
+		return 0
+	}
+	return bc.total.Load()
+}
+
+// WrapClient returns a copy of client whose response body reads are counted.
+// Only bytes actually read are counted, so bodies closed early (ranged
+// probes, HEAD requests) contribute what was transferred to the caller.
+func (bc *ByteCounter) WrapClient(client *http.Client) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	wrapped := *client
+	wrapped.Transport = &countingTransport{base: base, counter: bc}
+	return &wrapped
+}
+
+// countingTransport wraps response bodies to count the bytes read from them
+type countingTransport struct {
+	base    http.RoundTripper
+	counter *ByteCounter
+}
+
+// RoundTrip implements http.RoundTripper
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := ct.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		return resp, err
+	}
+	resp.Body = &countingBody{ReadCloser: resp.Body, counter: ct.counter}
+	return resp, nil
+}
+
+// countingBody counts bytes as they are read
+type countingBody struct {
+	io.ReadCloser
+	counter *ByteCounter
+}
+
+// Read implements io.Reader
+func (cb *countingBody) Read(p []byte) (int, error) {
+	n, err := cb.ReadCloser.Read(p)
+	cb.counter.Add(int64(n))
+	return n, err
+}
+
+// FormatBytes formats n as a human readable size, e.g. "1.5 MB"
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
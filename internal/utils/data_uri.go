@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// IsDataURI reports whether s is a data: URI such as
+// "data:image/png;base64,iVBORw0..."
+func IsDataURI(s string) bool {
+	return len(s) >= 5 && strings.EqualFold(s[:5], "data:")
+}
+
+// DecodeDataURI returns the media type (lowercased, without parameters) and
+// the decoded payload of a data: URI. Base64 payloads may be unpadded or
+// contain whitespace; others are percent-decoded.
+func DecodeDataURI(uri string) (mediaType string, data []byte, err error) {
+	if !IsDataURI(uri) {
+		return "", nil, errors.New("not a data URI")
+	}
+	meta, payload, found := strings.Cut(uri[5:], ",")
+	if !found {
+		return "", nil, errors.New("data URI has no payload")
+	}
+
+	params := strings.Split(meta, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(params[0]))
+	if mediaType == "" {
+		mediaType = "text/plain"
+	}
+
+	isBase64 := len(params) > 1 && strings.EqualFold(strings.TrimSpace(params[len(params)-1]), "base64")
+	if !isBase64 {
+		decoded, err := url.PathUnescape(payload)
+		if err != nil {
+			return "", nil, err
+		}
+		return mediaType, []byte(decoded), nil
+	}
+
+	// Some pages percent-encode or wrap the base64 text
+	if unescaped, err := url.PathUnescape(payload); err == nil {
+		payload = unescaped
+	}
+	payload = strings.Join(strings.Fields(payload), "")
+	data, err = base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "="))
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return mediaType, data, nil
+}
+
+// ShortURL abbreviates data: URIs for display, keeping their media type;
+// other URLs are returned unchanged
+func ShortURL(url string) string {
+	if !IsDataURI(url) {
+		return url
+	}
+	meta, _, _ := strings.Cut(url, ",")
+	return fmt.Sprintf("%s,… (%d chars)", meta, len(url))
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// domainRe finds a domain in free-form input, including Unicode labels and
+// punycode (xn--) TLDs
+var domainRe = regexp.MustCompile(`[\p{L}\p{N}_.-]+\.(?:xn--[a-z0-9-]+|\p{L}{2,})`)
+
+// NormalizeDomain turns a domain, URL or company name into an ASCII
+// (punycode) domain string; names without a domain get ".com" appended
+func NormalizeDomain(input string) string {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if domain := domainRe.FindString(input); domain != "" {
+		return toASCII(domain)
+	}
+	// Fallback: assume it's a name -> append .com
+	return toASCII(strings.ReplaceAll(input, " ", "") + ".com")
+}
+
+// toASCII converts a possibly internationalized domain to punycode, applying
+// IDNA case folding. Domains IDNA rejects are returned unchanged.
+func toASCII(domain string) string {
+	ascii, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return domain
+	}
+	return ascii
+}
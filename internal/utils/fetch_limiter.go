@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// FetchLimiter caps the number of requests in flight across every client it
+// wraps. A slot is held until the response body is closed, so open
+// connections stay bounded rather than just request starts.
+type FetchLimiter struct {
+	slots chan struct{}
+}
+
+// NewFetchLimiter creates a limiter allowing up to limit concurrent requests
+func NewFetchLimiter(limit int) *FetchLimiter {
+	return &FetchLimiter{slots: make(chan struct{}, limit)}
+}
+
+// WrapClient returns a copy of client whose requests share the limiter's slots
+func (fl *FetchLimiter) WrapClient(client *http.Client) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	wrapped := *client
+	wrapped.Transport = &fetchLimiterTransport{base: base, limiter: fl}
+	return &wrapped
+}
+
+// fetchLimiterTransport waits for a free slot before each request
+type fetchLimiterTransport struct {
+	base    http.RoundTripper
+	limiter *FetchLimiter
+}
+
+// RoundTrip implements http.RoundTripper
+func (ft *fetchLimiterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case ft.limiter.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	release := sync.OnceFunc(func() { <-ft.limiter.slots })
+
+	resp, err := ft.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody frees the request's slot once the body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close implements io.Closer
+func (rb *releasingBody) Close() error {
+	err := rb.ReadCloser.Close()
+	rb.release()
+	return err
+}
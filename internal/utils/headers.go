@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WithOriginHeaders returns a copy of client that adds the given headers to
+// requests for host (or its www variant) only, so credentials meant for one
+// publisher are never sent to third parties such as CDNs or logo providers
+func WithOriginHeaders(client *http.Client, host string, headers http.Header) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	wrapped := *client
+	wrapped.Transport = &originHeaderTransport{
+		base:    base,
+		host:    strings.TrimPrefix(strings.ToLower(host), "www."),
+		headers: headers,
+	}
+	return &wrapped
+}
+
+// MergeHeaders returns base with the keys set in override replaced by
+// override's values. Neither argument is modified.
+func MergeHeaders(base, override http.Header) http.Header {
+	if len(base) == 0 {
+		return override
+	}
+	merged := base.Clone()
+	for key, values := range override {
+		merged[key] = append([]string(nil), values...)
+	}
+	return merged
+}
+
+// originHeaderTransport adds headers to same-origin requests
+type originHeaderTransport struct {
+	base    http.RoundTripper
+	host    string
+	headers http.Header
+}
+
+// RoundTrip implements http.RoundTripper
+func (ot *originHeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := strings.TrimPrefix(strings.ToLower(req.URL.Hostname()), "www.")
+	if host != ot.host {
+		return ot.base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	for key, values := range ot.headers {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	return ot.base.RoundTrip(req)
+}
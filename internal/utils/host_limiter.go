@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HostLimiterConfig configures a HostLimiter
+type HostLimiterConfig struct {
+	// LatencyThreshold is the response latency above which a host is
+	// considered struggling and the delay between its requests grows
+	LatencyThreshold time.Duration
+	// InitialDelay is the first delay applied once a host slows down
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between two requests to the same host
+	MaxDelay time.Duration
+}
+
+// HostLimiter spaces out requests per host, adapting the delay to observed
+// latency: slow responses double the delay, fast ones halve it again
+type HostLimiter struct {
+	config HostLimiterConfig
+	mu     sync.Mutex
+	hosts  map[string]*hostState
+}
+
+// hostState tracks pacing for a single host
+type hostState struct {
+	delay    time.Duration
+	nextSlot time.Time
+}
+
+// NewHostLimiter creates a new per-host limiter
+func NewHostLimiter(config HostLimiterConfig) *HostLimiter {
+	return &HostLimiter{
+		config: config,
+		hosts:  make(map[string]*hostState),
+	}
+}
+
+// WrapClient returns a copy of client whose requests are paced per host
+func (hl *HostLimiter) WrapClient(client *http.Client) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	wrapped := *client
+	wrapped.Transport = &hostLimiterTransport{base: base, limiter: hl}
+	return &wrapped
+}
+
+// reserve books the next request slot for host and returns when it starts
+func (hl *HostLimiter) reserve(host string) time.Time {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+
+	state := hl.state(host)
+	slot := time.Now()
+	if state.nextSlot.After(slot) {
+		slot = state.nextSlot
+	}
+	state.nextSlot = slot.Add(state.delay)
+	return slot
+}
+
+// observe adjusts the delay for host based on a request's latency
+func (hl *HostLimiter) observe(host string, latency time.Duration) {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+
+	state := hl.state(host)
+	if latency > hl.config.LatencyThreshold {
+		state.delay *= 2
+		if state.delay < hl.config.InitialDelay {
+			state.delay = hl.config.InitialDelay
+		}
+		if state.delay > hl.config.MaxDelay {
+			state.delay = hl.config.MaxDelay
+		}
+		return
+	}
+
+	// Relax gradually once the host recovers
+	state.delay /= 2
+	if state.delay < hl.config.InitialDelay {
+		state.delay = 0
+	}
+}
+
+// state returns the state for host; callers must hold hl.mu
+func (hl *HostLimiter) state(host string) *hostState {
+	host = strings.ToLower(host)
+	state, ok := hl.hosts[host]
+	if !ok {
+		state = &hostState{}
+		hl.hosts[host] = state
+	}
+	return state
+}
+
+// hostLimiterTransport waits for the host's slot before each request
+type hostLimiterTransport struct {
+	base    http.RoundTripper
+	limiter *HostLimiter
+}
+
+// RoundTrip implements http.RoundTripper
+func (ht *hostLimiterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+
+	if wait := time.Until(ht.limiter.reserve(host)); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+
+	start := time.Now()
+	resp, err := ht.base.RoundTrip(req)
+	ht.limiter.observe(host, time.Since(start))
+	return resp, err
+}
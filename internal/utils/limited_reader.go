@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrReadLimitExceeded is returned by a LimitedReader once its source holds
+// more than the limit
+var ErrReadLimitExceeded = errors.New("read limit exceeded")
+
+// LimitedReader reads at most a fixed number of bytes from a source. Unlike
+// io.LimitReader it fails with ErrReadLimitExceeded rather than io.EOF when
+// the source goes on, so a capped body is not mistaken for a complete one.
+// It is safe for concurrent use.
+type LimitedReader struct {
+	mu        sync.Mutex
+	r         io.Reader
+	remaining int64
+}
+
+// NewLimitedReader returns a reader of at most limit bytes from r
+func NewLimitedReader(r io.Reader, limit int64) *LimitedReader {
+	return &LimitedReader{r: r, remaining: limit}
+}
+
+// Read implements io.Reader
+func (lr *LimitedReader) Read(p []byte) (int, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if lr.remaining <= 0 {
+		// Only data past the limit is an error; a source ending exactly at it
+		// reports its own EOF
+		var probe [1]byte
+		n, err := lr.r.Read(probe[:])
+		if n > 0 {
+			return 0, ErrReadLimitExceeded
+		}
+		return 0, err
+	}
+	if int64(len(p)) > lr.remaining {
+		p = p[:lr.remaining]
+	}
+	n, err := lr.r.Read(p)
+	lr.remaining -= int64(n)
+	return n, err
+}
@@ -3,51 +3,91 @@ package utils
 import (
 	"fmt"
 	"os"
+	"sync"
 	"time"
 )
 
-// Loader provides animated console loading indicators
+// Quiet turns loaders and progress bars into no-ops, e.g. for cron runs
+var Quiet bool
+
+// Loader provides animated console loading indicators. Stop is safe to call
+// any number of times, with or without a prior Start.
 type Loader struct {
-	message string
-	done    chan bool
+	message  string
+	done     chan struct{} // Closed by Stop
+	finished chan struct{} // Closed when the animation goroutine returns
+
+	mu       sync.Mutex
+	running  bool // Start launched the animation
+	stopped  bool // Stop was called; Start is then a no-op
+	stopOnce sync.Once
 }
 
 // NewLoader creates a new loader with a message
 func NewLoader(message string) *Loader {
 	return &Loader{
-		message: message,
-		done:    make(chan bool),
+		message:  message,
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
 	}
 }
 
-// Start begins the loading animation
+// Start begins the loading animation. Calls after the first, or after Stop,
+// do nothing.
 func (l *Loader) Start() {
-	go func() {
-		frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-		i := 0
-		for {
-			select {
-			case <-l.done:
-				return
-			default:
-				fmt.Printf("\r%s %s", frames[i], l.message)
-				os.Stdout.Sync()
-				time.Sleep(100 * time.Millisecond)
-				i = (i + 1) % len(frames)
-			}
+	if Quiet {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.running || l.stopped {
+		return
+	}
+	l.running = true
+	go l.animate()
+}
+
+// animate draws frames until done is closed
+func (l *Loader) animate() {
+	defer close(l.finished)
+
+	frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for i := 0; ; i = (i + 1) % len(frames) {
+		fmt.Printf("\r%s %s", frames[i], l.message)
+		os.Stdout.Sync()
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
 		}
-	}()
+	}
 }
 
-// Stop stops the loading animation and clears the line
+// Stop stops the loading animation and clears the line. It waits for the
+// animation to draw its last frame so the line stays clear.
 func (l *Loader) Stop() {
-	l.done <- true
-	fmt.Printf("\r%s\r", "                                                                                ")
-	os.Stdout.Sync()
+	l.stopOnce.Do(func() {
+		l.mu.Lock()
+		l.stopped = true
+		running := l.running
+		l.mu.Unlock()
+
+		close(l.done)
+		if !running {
+			return
+		}
+		<-l.finished
+		fmt.Printf("\r%s\r", "                                                                                ")
+		os.Stdout.Sync()
+	})
 }
 
-// ProgressBar shows a progress bar for a specific task
+// ProgressBar shows a progress bar for a specific task. It is safe for
+// concurrent use.
 type ProgressBar struct {
+	mu      sync.Mutex
 	total   int
 	current int
 	message string
@@ -64,7 +104,27 @@ func NewProgressBar(total int, message string) *ProgressBar {
 
 // Update updates the progress bar
 func (pb *ProgressBar) Update(current int) {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	pb.render(current)
+}
+
+// Increment advances the progress bar by one step
+func (pb *ProgressBar) Increment() {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	pb.render(pb.current + 1)
+}
+
+// render draws the bar at current; callers must hold pb.mu
+func (pb *ProgressBar) render(current int) {
+	if current > pb.total {
+		current = pb.total
+	}
 	pb.current = current
+	if Quiet {
+		return
+	}
 	percentage := float64(current) / float64(pb.total) * 100
 	barLength := 30
 	filledLength := int(float64(barLength) * percentage / 100)
@@ -85,5 +145,7 @@ func (pb *ProgressBar) Update(current int) {
 // Complete marks the progress bar as complete
 func (pb *ProgressBar) Complete() {
 	pb.Update(pb.total)
-	fmt.Println() // Move to next line
+	if !Quiet {
+		fmt.Println() // Move to next line
+	}
 }
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Percentiles returns the nearest-rank percentile of durations for each of
+// ps (0-100), in the same order. durations is not modified; an empty slice
+// yields zeros.
+func Percentiles(durations []time.Duration, ps ...float64) []time.Duration {
+	out := make([]time.Duration, len(ps))
+	if len(durations) == 0 {
+		return out
+	}
+
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	for i, p := range ps {
+		rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+		out[i] = sorted[min(max(rank-1, 0), len(sorted)-1)]
+	}
+	return out
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrProviderDisabled is returned for requests to a provider that was disabled
+// after being persistently rate limited
+var ErrProviderDisabled = errors.New("provider disabled after repeated rate limiting")
+
+// ProviderGuardConfig configures a ProviderGuard
+type ProviderGuardConfig struct {
+	Hosts        []string      // Third-party provider hosts, e.g. logo.clearbit.com
+	Retries      int           // Retries after an HTTP 429 response
+	Backoff      time.Duration // Initial backoff, doubled on each retry
+	MaxBackoff   time.Duration // Cap on a single backoff, including Retry-After
+	DisableAfter int           // Requests still rate limited after retries before disabling; 0 never disables
+	Logger       *slog.Logger  // Receives retry and disable events (default slog.Default())
+}
+
+// ProviderGuard retries third-party provider requests that are rate limited
+// (HTTP 429) and disables a provider for the rest of the run once it stays
+// rate limited. Requests to other hosts pass through untouched.
+type ProviderGuard struct {
+	config   ProviderGuardConfig
+	mu       sync.Mutex
+	strikes  map[string]int
+	disabled map[string]bool
+}
+
+// NewProviderGuard creates a new provider guard
+func NewProviderGuard(config ProviderGuardConfig) *ProviderGuard {
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	return &ProviderGuard{
+		config:   config,
+		strikes:  make(map[string]int),
+		disabled: make(map[string]bool),
+	}
+}
+
+// WrapClient returns a copy of client whose provider requests are guarded
+func (pg *ProviderGuard) WrapClient(client *http.Client) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	wrapped := *client
+	wrapped.Transport = &providerGuardTransport{base: base, guard: pg}
+	return &wrapped
+}
+
+// IsDisabled reports whether the provider at host has been disabled
+func (pg *ProviderGuard) IsDisabled(host string) bool {
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+	return pg.disabled[strings.ToLower(host)]
+}
+
+// isProvider reports whether host is one of the guarded providers
+func (pg *ProviderGuard) isProvider(host string) bool {
+	for _, provider := range pg.config.Hosts {
+		if strings.EqualFold(host, provider) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordOutcome tracks whether a provider request ended rate limited
+func (pg *ProviderGuard) recordOutcome(host string, rateLimited bool) {
+	host = strings.ToLower(host)
+
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+
+	if !rateLimited {
+		pg.strikes[host] = 0
+		return
+	}
+
+	pg.strikes[host]++
+	if pg.config.DisableAfter > 0 && pg.strikes[host] >= pg.config.DisableAfter && !pg.disabled[host] {
+		pg.disabled[host] = true
+		pg.config.Logger.Warn("provider persistently rate limited, disabling it for the rest of the run", "host", host)
+	}
+}
+
+// backoff returns how long to wait before the given retry attempt
+func (pg *ProviderGuard) backoff(resp *http.Response, attempt int) time.Duration {
+	wait := pg.config.Backoff << attempt
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		wait = time.Duration(seconds) * time.Second
+	}
+	if pg.config.MaxBackoff > 0 && wait > pg.config.MaxBackoff {
+		wait = pg.config.MaxBackoff
+	}
+	return wait
+}
+
+// providerGuardTransport applies the guard to provider requests
+type providerGuardTransport struct {
+	base  http.RoundTripper
+	guard *ProviderGuard
+}
+
+// RoundTrip implements http.RoundTripper
+func (pt *providerGuardTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if !pt.guard.isProvider(host) {
+		return pt.base.RoundTrip(req)
+	}
+	if pt.guard.IsDisabled(host) {
+		return nil, ErrProviderDisabled
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := pt.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests {
+			pt.guard.recordOutcome(host, false)
+			return resp, nil
+		}
+		if attempt >= pt.guard.config.Retries {
+			pt.guard.recordOutcome(host, true)
+			return resp, nil
+		}
+
+		wait := pt.guard.backoff(resp, attempt)
+		resp.Body.Close()
+		pt.guard.config.Logger.Info("retrying rate-limited provider request",
+			"host", host, "attempt", attempt+1, "wait", wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
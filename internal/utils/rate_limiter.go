@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// HostRateLimiter caps the request rate to each host with a token bucket,
+// so workers that happen to hit the same host cannot burst it
+type HostRateLimiter struct {
+	limit rate.Limit
+	burst int
+	mu    sync.Mutex
+	hosts map[string]*rate.Limiter
+}
+
+// NewHostRateLimiter creates a limiter allowing requestsPerSecond requests
+// per host, with bursts of up to one second's worth
+func NewHostRateLimiter(requestsPerSecond float64) *HostRateLimiter {
+	return &HostRateLimiter{
+		limit: rate.Limit(requestsPerSecond),
+		burst: max(1, int(requestsPerSecond)),
+		hosts: make(map[string]*rate.Limiter),
+	}
+}
+
+// WrapClient returns a copy of client whose requests are rate limited per host
+func (hr *HostRateLimiter) WrapClient(client *http.Client) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	wrapped := *client
+	wrapped.Transport = &hostRateLimiterTransport{base: base, limiter: hr}
+	return &wrapped
+}
+
+// limiter returns the token bucket for host, creating it on first use
+func (hr *HostRateLimiter) limiter(host string) *rate.Limiter {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	host = strings.ToLower(host)
+	limiter, ok := hr.hosts[host]
+	if !ok {
+		limiter = rate.NewLimiter(hr.limit, hr.burst)
+		hr.hosts[host] = limiter
+	}
+	return limiter
+}
+
+// hostRateLimiterTransport waits for a token for the request's host
+type hostRateLimiterTransport struct {
+	base    http.RoundTripper
+	limiter *HostRateLimiter
+}
+
+// RoundTrip implements http.RoundTripper
+func (ht *hostRateLimiterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := ht.limiter.limiter(req.URL.Hostname()).Wait(req.Context()); err != nil {
+		// Wait fails early when the token would arrive after the deadline
+		if req.Context().Err() == nil {
+			return nil, fmt.Errorf("rate limit wait for %s: %w", req.URL.Hostname(), context.DeadlineExceeded)
+		}
+		return nil, err
+	}
+	return ht.base.RoundTrip(req)
+}
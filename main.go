@@ -1,10 +1,12 @@
 package main
 
 import (
+	"os"
+
 	"github.com/Tanmay-Thanvi/logo-crawler/internal/app"
 )
 
 func main() {
 	app := app.NewLogoCrawlerApp()
-	app.Run()
+	os.Exit(app.Run())
 }